@@ -1,7 +1,12 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/alecthomas/kong"
 
@@ -9,12 +14,48 @@ import (
 )
 
 func main() {
-	var cli app.CLI
-	ctx := kong.Parse(&cli, kong.Vars{"version": app.GetVersion()})
+	var cmds app.Commands
+	kctx := kong.Parse(&cmds, kong.Vars{"version": app.GetVersion()})
 
-	if err := app.Run(&cli); err != nil {
+	// SIGINT/SIGTERM cancel the context so commands can shut down the server, remove its
+	// container and close open files before the process exits
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	received := make(chan os.Signal, 1)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		log.Printf("Received %v, shutting down", sig)
+		received <- sig
+		// A second signal terminates the process immediately
+		signal.Stop(signals)
+		cancel()
+	}()
+
+	kctx.BindTo(ctx, (*context.Context)(nil))
+	err := kctx.Run()
+
+	select {
+	case sig := <-received:
+		cancel()
+		reraise(sig)
+	default:
+	}
+	if err != nil {
 		log.Fatalf("Error: %v", err)
 	}
+}
 
-	_ = ctx
+// reraise terminates the process with sig once cleanup has run, so the parent sees the
+// conventional signal exit status
+func reraise(sig os.Signal) {
+	signal.Reset(sig)
+	if p, err := os.FindProcess(os.Getpid()); err == nil && p.Signal(sig) == nil {
+		time.Sleep(time.Second) // Delivery is asynchronous
+	}
+	if s, ok := sig.(syscall.Signal); ok {
+		os.Exit(128 + int(s))
+	}
+	os.Exit(1)
 }
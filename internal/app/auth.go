@@ -0,0 +1,283 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spandigital/mcp-server-dump/internal/auth"
+	"github.com/spandigital/mcp-server-dump/internal/transport"
+)
+
+// AuthCmd manages the OAuth tokens and client registrations cached for MCP servers
+type AuthCmd struct {
+	List   AuthListCmd   `kong:"cmd,help='List servers with a cached OAuth token or client registration'"`
+	Show   AuthShowCmd   `kong:"cmd,help='Show the cached OAuth token and client registration for a server (secrets are never shown)'"`
+	Login  AuthLoginCmd  `kong:"cmd,help='Authenticate with a server and cache its OAuth token'"`
+	Logout AuthLogoutCmd `kong:"cmd,help='Remove the cached OAuth token and client registration for a server'"`
+}
+
+// AuthListCmd lists the servers with cached credentials
+type AuthListCmd struct {
+	TokenStoreOptions `kong:"embed"`
+}
+
+// Run executes the auth list command
+func (c *AuthListCmd) Run() error {
+	store, err := auth.NewTokenStore(c.TokenStore)
+	if err != nil {
+		return err
+	}
+	return listCredentials(os.Stdout, store, time.Now())
+}
+
+// AuthShowCmd shows the cached credentials for one server
+type AuthShowCmd struct {
+	Endpoint string `kong:"arg,help='MCP server endpoint URL'"`
+
+	TokenStoreOptions `kong:"embed"`
+}
+
+// Run executes the auth show command
+func (c *AuthShowCmd) Run() error {
+	store, err := auth.NewTokenStore(c.TokenStore)
+	if err != nil {
+		return err
+	}
+	return showCredentials(os.Stdout, store, transport.HTTPEndpoint(c.Endpoint), time.Now())
+}
+
+// AuthLogoutCmd removes cached credentials
+type AuthLogoutCmd struct {
+	Endpoint string `kong:"arg,optional,help='MCP server endpoint URL'"`
+	All      bool   `kong:"help='Remove the cached tokens and client registrations for every server'"`
+
+	TokenStoreOptions `kong:"embed"`
+}
+
+// Run executes the auth logout command
+func (c *AuthLogoutCmd) Run() error {
+	switch {
+	case c.All && c.Endpoint != "":
+		return errors.New("give either an endpoint or --all, not both")
+	case !c.All && c.Endpoint == "":
+		return errors.New("an endpoint or --all is required")
+	}
+
+	store, err := auth.NewTokenStore(c.TokenStore)
+	if err != nil {
+		return err
+	}
+	if c.All {
+		return logoutAll(os.Stdout, store)
+	}
+	return logout(os.Stdout, store, transport.HTTPEndpoint(c.Endpoint))
+}
+
+// AuthLoginCmd runs the OAuth flow for a server ahead of time and caches the token,
+// so later dumps (for example in CI) start without an interactive login
+type AuthLoginCmd struct {
+	Server string `kong:"arg,name='endpoint',help='MCP server endpoint URL (http(s):// or ws(s)://)'"`
+
+	// Connection options for TLS, proxies and the OAuth flags
+	ConnectionOptions `kong:"embed"`
+}
+
+// Run executes the auth login command
+func (c *AuthLoginCmd) Run(ctx context.Context) error {
+	conn := c.ConnectionOptions
+	conn.Endpoint = c.Server
+	// There is no server process to start, only an endpoint to discover OAuth metadata from
+	if conn.Transport == "command" {
+		conn.Transport = "auto"
+	}
+
+	transportConfig := newTransportConfig(&conn, nil)
+	ctx, oauthConfig, err := prepareOAuth(ctx, &conn, &transportConfig)
+	if err != nil {
+		return err
+	}
+	if oauthConfig == nil {
+		return fmt.Errorf("%s does not require OAuth (no authorization server metadata was found)", transport.HTTPEndpoint(conn.Endpoint))
+	}
+
+	token, err := auth.Authorize(ctx, oauthConfig)
+	if err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+
+	// The token is cached even with --oauth-no-cache, which is what login is for
+	store := oauthConfig.TokenStore
+	if store == nil {
+		if store, err = auth.NewTokenStore(conn.TokenStore); err != nil {
+			return err
+		}
+	}
+	cache := auth.FromOAuth2Token(token, oauthConfig.ResourceURI, oauthConfig.Scopes)
+	if err := store.Save(cache); err != nil {
+		return fmt.Errorf("failed to cache token: %w", err)
+	}
+
+	fmt.Printf("✓ Logged in to %s (token %s)\n", oauthConfig.ResourceURI, tokenStatus(cache, time.Now()))
+	return nil
+}
+
+// listCredentials writes a table of the servers with a cached token or client registration
+func listCredentials(w io.Writer, store auth.TokenStore, now time.Time) error {
+	tokenServers, err := store.List()
+	if err != nil {
+		return err
+	}
+	registeredServers, err := auth.ListRegisteredServers()
+	if err != nil {
+		return err
+	}
+
+	servers := slices.Compact(slices.Sorted(slices.Values(append(tokenServers, registeredServers...))))
+	if len(servers) == 0 {
+		_, err := fmt.Fprintln(w, "No cached OAuth credentials")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "SERVER\tTOKEN\tCLIENT REGISTRATION")
+	for _, server := range servers {
+		token, registration := loadCredentials(store, server)
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", server, tokenStatus(token, now), registrationSummary(registration))
+	}
+	return tw.Flush()
+}
+
+// showCredentials writes the details of the cached token and client registration for a server.
+// Access tokens, refresh tokens and client secrets are reported by presence only.
+func showCredentials(w io.Writer, store auth.TokenStore, server string, now time.Time) error {
+	token, registration := loadCredentials(store, server)
+	if token == nil && registration == nil {
+		return fmt.Errorf("no cached OAuth credentials for %s", server)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "Server:\t%s\n", server)
+
+	if token == nil {
+		_, _ = fmt.Fprintln(tw, "Token:\tnone")
+	} else {
+		_, _ = fmt.Fprintf(tw, "Token:\t%s\n", tokenStatus(token, now))
+		_, _ = fmt.Fprintf(tw, "  Type:\t%s\n", valueOrNone(token.TokenType))
+		if !token.Expiry.IsZero() {
+			_, _ = fmt.Fprintf(tw, "  Expires:\t%s\n", token.Expiry.Local().Format(time.RFC3339))
+		}
+		_, _ = fmt.Fprintf(tw, "  Scopes:\t%s\n", valueOrNone(strings.Join(token.Scopes, " ")))
+		_, _ = fmt.Fprintf(tw, "  Refresh token:\t%s\n", yesNo(token.RefreshToken != ""))
+	}
+
+	if registration == nil {
+		_, _ = fmt.Fprintln(tw, "Client registration:\tnone")
+	} else {
+		_, _ = fmt.Fprintln(tw, "Client registration:\t")
+		_, _ = fmt.Fprintf(tw, "  Client ID:\t%s\n", registration.ClientID)
+		_, _ = fmt.Fprintf(tw, "  Auth method:\t%s\n", valueOrNone(registration.TokenEndpointAuthMethod))
+		_, _ = fmt.Fprintf(tw, "  Client secret:\t%s\n", yesNo(registration.ClientSecret != ""))
+		if !registration.RegisteredAt.IsZero() {
+			_, _ = fmt.Fprintf(tw, "  Registered:\t%s\n", registration.RegisteredAt.Local().Format(time.RFC3339))
+		}
+	}
+	return tw.Flush()
+}
+
+// logout removes the cached token and client registration for a server.
+// Cache files are removed even if they cannot be read.
+func logout(w io.Writer, store auth.TokenStore, server string) error {
+	token, registration := loadCredentials(store, server)
+
+	if err := store.Delete(server); err != nil {
+		return err
+	}
+	if err := auth.ClearClientRegistration(server); err != nil {
+		return err
+	}
+
+	if token == nil && registration == nil {
+		_, err := fmt.Fprintf(w, "No cached OAuth credentials for %s\n", server)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "✓ Logged out of %s\n", server)
+	return err
+}
+
+// logoutAll removes every cached token and client registration
+func logoutAll(w io.Writer, store auth.TokenStore) error {
+	if err := store.DeleteAll(); err != nil {
+		return err
+	}
+	if err := auth.ClearAllClientRegistrations(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintln(w, "✓ Removed all cached OAuth tokens and client registrations")
+	return err
+}
+
+// loadCredentials loads the cached token and client registration for a server, either of which may be nil.
+// Unreadable cache files are reported as warnings and treated as missing.
+func loadCredentials(store auth.TokenStore, server string) (*auth.TokenCache, *auth.ClientRegistration) {
+	token, err := store.Load(server)
+	if err != nil {
+		log.Printf("Warning: failed to load cached token for %s: %v", server, err)
+	}
+	registration, err := auth.LoadClientRegistration(server)
+	if err != nil {
+		log.Printf("Warning: failed to load client registration for %s: %v", server, err)
+	}
+	return token, registration
+}
+
+// tokenStatus summarizes whether a cached token is still usable
+func tokenStatus(token *auth.TokenCache, now time.Time) string {
+	switch {
+	case token == nil:
+		return "-"
+	case token.Expiry.IsZero():
+		return "valid (no expiry)"
+	case now.Before(token.Expiry):
+		return fmt.Sprintf("valid, expires in %s", token.Expiry.Sub(now).Round(time.Second))
+	case token.RefreshToken != "":
+		return "expired, refreshable"
+	default:
+		return "expired"
+	}
+}
+
+// registrationSummary describes a client registration in a single column
+func registrationSummary(registration *auth.ClientRegistration) string {
+	if registration == nil {
+		return "-"
+	}
+	if registration.TokenEndpointAuthMethod == "" {
+		return registration.ClientID
+	}
+	return fmt.Sprintf("%s (%s)", registration.ClientID, registration.TokenEndpointAuthMethod)
+}
+
+// valueOrNone returns value, or "none" when it is empty
+func valueOrNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
+
+// yesNo formats a presence flag
+func yesNo(present bool) string {
+	if present {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,178 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/spandigital/mcp-server-dump/internal/auth"
+	"github.com/spandigital/mcp-server-dump/internal/configdir"
+)
+
+// seedCredentials caches a token for one server and a client registration for another and both
+func seedCredentials(t *testing.T, now time.Time) {
+	t.Helper()
+	t.Setenv(configdir.HomeEnv, t.TempDir())
+
+	token := &oauth2.Token{AccessToken: "secret-access", RefreshToken: "secret-refresh", TokenType: "Bearer", Expiry: now.Add(time.Hour)}
+	if err := auth.SaveToken(token, "https://both.example.com/mcp", []string{"mcp:tools", "mcp:prompts"}); err != nil {
+		t.Fatalf("SaveToken failed: %v", err)
+	}
+	expired := &oauth2.Token{AccessToken: "secret-expired", TokenType: "Bearer", Expiry: now.Add(-time.Hour)}
+	if err := auth.SaveToken(expired, "https://token.example.com/mcp", nil); err != nil {
+		t.Fatalf("SaveToken failed: %v", err)
+	}
+	for _, server := range []string{"https://both.example.com/mcp", "https://registered.example.com/mcp"} {
+		if err := auth.SaveClientRegistration(&auth.ClientRegistration{
+			ResourceURI:             server,
+			ClientID:                "dcr-client",
+			ClientSecret:            "secret-client",
+			RegistrationAccessToken: "secret-registration",
+			TokenEndpointAuthMethod: auth.AuthMethodNone,
+			RegisteredAt:            now.Add(-24 * time.Hour),
+		}); err != nil {
+			t.Fatalf("SaveClientRegistration failed: %v", err)
+		}
+	}
+}
+
+// fileStore returns the default token store the seeded tokens are cached in
+func fileStore(t *testing.T) auth.TokenStore {
+	t.Helper()
+	store, err := auth.NewTokenStore(auth.TokenStoreFile)
+	if err != nil {
+		t.Fatalf("NewTokenStore failed: %v", err)
+	}
+	return store
+}
+
+func TestAuthList(t *testing.T) {
+	now := time.Now()
+	seedCredentials(t, now)
+
+	var out bytes.Buffer
+	if err := listCredentials(&out, fileStore(t), now); err != nil {
+		t.Fatalf("listCredentials failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected a header and 3 servers, got:\n%s", out.String())
+	}
+	want := [][]string{
+		{"https://both.example.com/mcp", "valid, expires in 1h0m0s", "dcr-client (none)"},
+		{"https://registered.example.com/mcp", "-", "dcr-client (none)"},
+		{"https://token.example.com/mcp", "expired", "-"},
+	}
+	for i, fields := range want {
+		for _, field := range fields {
+			if !strings.Contains(lines[i+1], field) {
+				t.Errorf("Expected line %d to contain %q, got %q", i+1, field, lines[i+1])
+			}
+		}
+	}
+	if strings.Contains(out.String(), "secret") {
+		t.Errorf("List output leaks a secret:\n%s", out.String())
+	}
+}
+
+func TestAuthShow(t *testing.T) {
+	now := time.Now()
+	seedCredentials(t, now)
+
+	var out bytes.Buffer
+	if err := showCredentials(&out, fileStore(t), "https://both.example.com/mcp", now); err != nil {
+		t.Fatalf("showCredentials failed: %v", err)
+	}
+	// Compare with the column padding collapsed
+	shown := strings.Join(strings.Fields(out.String()), " ")
+	for _, want := range []string{"valid, expires in 1h0m0s", "Scopes: mcp:tools mcp:prompts", "Refresh token: yes", "Client ID: dcr-client", "Client secret: yes"} {
+		if !strings.Contains(shown, want) {
+			t.Errorf("Expected show output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "secret-") {
+		t.Errorf("Show output leaks a secret:\n%s", out.String())
+	}
+
+	if err := showCredentials(&out, fileStore(t), "https://unknown.example.com/mcp", now); err == nil {
+		t.Error("Expected an error for a server without cached credentials")
+	}
+}
+
+func TestAuthLogout(t *testing.T) {
+	now := time.Now()
+	seedCredentials(t, now)
+	store := fileStore(t)
+
+	// WebSocket endpoints share the credentials of their HTTP form
+	var out bytes.Buffer
+	cmd := &AuthLogoutCmd{Endpoint: "wss://both.example.com/mcp"}
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+	if token, registration := loadCredentials(store, "https://both.example.com/mcp"); token != nil || registration != nil {
+		t.Errorf("Expected the token and registration to be removed, got %+v, %+v", token, registration)
+	}
+	if token, _ := loadCredentials(store, "https://token.example.com/mcp"); token == nil {
+		t.Error("Expected other servers to keep their credentials")
+	}
+
+	if err := logoutAll(&out, store); err != nil {
+		t.Fatalf("Logout --all failed: %v", err)
+	}
+	out.Reset()
+	if err := listCredentials(&out, store, now); err != nil {
+		t.Fatalf("listCredentials failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "No cached OAuth credentials") {
+		t.Errorf("Expected no credentials after logout --all, got:\n%s", out.String())
+	}
+
+	for _, invalid := range []AuthLogoutCmd{{}, {Endpoint: "https://both.example.com/mcp", All: true}} {
+		if err := invalid.Run(); err == nil {
+			t.Errorf("Expected an error for logout %+v", invalid)
+		}
+	}
+}
+
+func TestAuthLogin_ClientCredentials(t *testing.T) {
+	t.Setenv(configdir.HomeEnv, t.TempDir())
+
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, ok := r.BasicAuth(); !ok || id != "ci-client" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "login-token", "token_type": "Bearer", "expires_in": 3600})
+	}))
+	defer tokenServer.Close()
+
+	cmd := &AuthLoginCmd{
+		Server: "https://mcp.example.com/mcp",
+		ConnectionOptions: ConnectionOptions{
+			Transport:         "command",
+			OAuthClientID:     "ci-client",
+			OAuthClientSecret: "s3cret",
+			OAuthTokenURL:     tokenServer.URL + "/token",
+			OAuthFlow:         string(auth.FlowTypeClientCredentials),
+			OAuthAuthMethod:   auth.AuthMethodClientSecretBasic,
+		},
+	}
+	if err := cmd.Run(context.Background()); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	cached, err := auth.LoadToken("https://mcp.example.com/mcp")
+	if err != nil || cached == nil || cached.AccessToken != "login-token" {
+		t.Errorf("Expected the login token to be cached, got %+v, %v", cached, err)
+	}
+}
@@ -1,38 +1,82 @@
 package app
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/alecthomas/kong"
+
+	"github.com/spandigital/mcp-server-dump/internal/configdir"
 )
 
-// CLI represents the command line interface configuration
-type CLI struct {
+// Commands is the top-level command line. Dumping a server is the default command,
+// so the original "mcp-server-dump [flags] <command> [args...]" form keeps working.
+type Commands struct {
 	// Version flag
 	Version kong.VersionFlag `kong:"short='v',help='Show version information'"`
 
-	// Output options
-	Output string `kong:"short='o',help='Output file for documentation (defaults to stdout, required for hugo format as directory)'"`
-	Format string `kong:"short='f',default='markdown',enum='markdown,json,html,pdf,hugo',help='Output format'"`
-	NoTOC  bool   `kong:"help='Disable table of contents in markdown output'"`
+	// Location of cached OAuth tokens, client registrations and detected transports
+	ConfigDir string `kong:"type='path',help='Directory for cached OAuth tokens, client registrations and detected transports (defaults to $MCP_SERVER_DUMP_HOME, then $XDG_CONFIG_HOME/mcp-server-dump, then ~/.config/mcp-server-dump)'"`
 
-	// Frontmatter options
-	Frontmatter       bool     `kong:"short='F',help='Include frontmatter in markdown output (enabled by default for Hugo format)'"`
-	FrontmatterField  []string `kong:"short='M',help='Add custom frontmatter field (format: key:value), can be used multiple times'"`
-	FrontmatterFormat string   `kong:"default='yaml',enum='yaml,toml,json',help='Frontmatter format'"`
+	Dump CLI     `kong:"cmd,default='withargs',help='Dump documentation for an MCP server (default command)'"`
+	Diff DiffCmd `kong:"cmd,help='Compare two server dumps (or a live server against a baseline) and report API changes'"`
+	Auth AuthCmd `kong:"cmd,help='Manage cached OAuth tokens and client registrations'"`
+}
 
+// AfterApply points the configuration directory at --config-dir once flags are parsed, before any command runs
+func (c *Commands) AfterApply() error {
+	configdir.Set(c.ConfigDir)
+	return nil
+}
+
+// ConnectionOptions holds the flags needed to connect to an MCP server.
+// They are shared by every command that talks to a live server.
+type ConnectionOptions struct {
 	// Transport selection
-	Transport string `kong:"short='t',default='command',enum='command,sse,streamable',help='Transport type'"`
+	Transport string `kong:"short='t',default='command',enum='command,sse,streamable,websocket,auto',help='Transport type (auto detects streamable or SSE for an HTTP endpoint)'"`
 
 	// Transport-specific options
-	Endpoint string        `kong:"help='HTTP endpoint for SSE/Streamable transports'"`
-	Timeout  time.Duration `kong:"default='30s',help='HTTP timeout for SSE/Streamable transports'"`
-	Headers  []string      `kong:"short='H',help='HTTP headers for SSE/Streamable transports (format: Key:Value)'"`
-
-	// Context configuration
-	ContextFile   []string `kong:"help='Path to context configuration files (YAML/JSON), can be used multiple times'"`
-	ServerCommand string   `kong:"help='Server command for explicit command transport'"`
+	Endpoint string        `kong:"help='Endpoint URL for SSE/Streamable/WebSocket transports (http(s):// or ws(s)://)'"`
+	Timeout  time.Duration `kong:"default='30s',help='HTTP timeout for SSE/Streamable transports (handshake timeout for WebSocket)'"`
+	Headers  []string      `kong:"short='H',help='HTTP headers for SSE/Streamable transports and the WebSocket handshake (format: Key:Value)'"`
+
+	// TLS options for HTTP transports, also applied to OAuth discovery and token requests
+	CACert             string `kong:"name='ca-cert',help='PEM file of CA certificates to trust in addition to the system roots'"`
+	ClientCert         string `kong:"help='PEM client certificate for mutual TLS (requires --client-key)'"`
+	ClientKey          string `kong:"help='PEM private key for --client-cert'"`
+	TLSServerName      string `kong:"name='tls-server-name',help='Server name to verify the certificate against and send via SNI'"`
+	InsecureSkipVerify bool   `kong:"help='Disable TLS certificate verification (insecure, for testing only)'"`
+
+	// Network route for HTTP transports, also applied to OAuth discovery and token requests
+	Proxy      string `kong:"help='Proxy URL for HTTP transports (http, https or socks5; hosts in NO_PROXY are reached directly)'"`
+	UnixSocket string `kong:"help='Unix domain socket to connect to for the --endpoint host (for local gateways)'"`
+
+	// Retries for transient failures when connecting, listing and sending HTTP requests
+	RetryAttempts   int           `kong:"default='3',help='Maximum attempts for connecting, each list request and each HTTP request (1 disables retries)'"`
+	RetryBackoff    time.Duration `kong:"default='500ms',help='Delay before the first retry, doubled for each further retry with jitter'"`
+	RetryMaxBackoff time.Duration `kong:"default='10s',help='Maximum delay between retries'"`
+	RetryStatus     []int         `kong:"default='429,502,503',help='HTTP status codes to retry (comma-separated)'"`
+
+	ServerCommand string `kong:"help='Server command for explicit command transport'"`
+
+	// Command transport process options
+	Env      []string `kong:"short='e',sep='none',help='Environment variable for the server process (format: KEY=VALUE, or KEY to pass through), can be used multiple times'"`
+	EnvFile  []string `kong:"sep='none',help='Path to a dotenv-style file of environment variables for the server process, can be used multiple times'"`
+	ClearEnv bool     `kong:"help='Start the server process with only the variables given by --env and --env-file'"`
+	Cwd      string   `kong:"help='Working directory for the server process'"`
+
+	// Container options for the command transport
+	ContainerImage   string   `kong:"help='Run the server inside this container image with docker or podman (the server command, if any, runs in the container)'"`
+	ContainerRuntime string   `kong:"help='Container runtime CLI to use (docker or podman, detected from PATH by default)'"`
+	ContainerMount   []string `kong:"sep='none',help='Bind mount for the server container (format: host-path:container-path[:ro|rw]), can be used multiple times'"`
+	ContainerNetwork string   `kong:"default='none',help='Network for the server container (none isolates the server)'"`
+
+	// Command transport diagnostics
+	Verbose   bool   `kong:"help='Stream the server process stderr to stderr, prefixed with [server]'"`
+	ServerLog string `kong:"help='Save the server process stderr to this file'"`
 
 	// OAuth 2.1 authentication options
 	OAuthClientID     string   `kong:"name='oauth-client-id',help='OAuth 2.1 client ID for authenticated MCP server access'"`
@@ -42,17 +86,70 @@ type CLI struct {
 	OAuthTokenURL     string   `kong:"name='oauth-token-url',help='OAuth token endpoint URL (normally discovered automatically)'"`
 	OAuthRedirectPort int      `kong:"name='oauth-redirect-port',default='8080',help='Port for OAuth loopback redirect (default 8080 for compatibility)'"`
 	OAuthNoCache      bool     `kong:"name='oauth-no-cache',help='Disable OAuth token caching (always require fresh authentication)'"`
-	OAuthFlow         string   `kong:"name='oauth-flow',default='auto',enum='auto,authorization-code,device,client-credentials',help='OAuth flow type (auto-detects by default)'"`
+	OAuthFlow         string   `kong:"name='oauth-flow',default='auto',enum='auto,authorization-code,device,client-credentials',help='OAuth flow type (auto-detects by default; client-credentials needs --oauth-client-id and --oauth-client-secret)'"`
+	OAuthAuthMethod   string   `kong:"name='oauth-auth-method',default='auto',enum='auto,client_secret_basic,client_secret_post,private_key_jwt',help='How the client authenticates to the token endpoint (auto uses private_key_jwt with --oauth-private-key, otherwise tries HTTP Basic, then form parameters)'"`
+	OAuthPrivateKey   string   `kong:"name='oauth-private-key',type='path',help='PEM private key for private_key_jwt client authentication (RFC 7523) instead of a client secret'"`
+	OAuthKeyID        string   `kong:"name='oauth-key-id',help='Key ID (kid) sent in private_key_jwt assertions'"`
+	OAuthSigningAlg   string   `kong:"name='oauth-signing-alg',help='Signing algorithm for private_key_jwt assertions (RS256, PS256, ES256, EdDSA, ...; defaults to the key type)'"`
+
+	// Where OAuth tokens are cached
+	TokenStoreOptions `kong:"embed"`
+}
+
+// TokenStoreOptions selects the backend for cached OAuth tokens, shared by the dump and auth commands
+type TokenStoreOptions struct {
+	TokenStore string `kong:"default='file',enum='file,encrypted,keyring',help='Where to cache OAuth tokens: file (plaintext, owner-only), encrypted (key from MCP_SERVER_DUMP_TOKEN_KEY or MCP_SERVER_DUMP_TOKEN_PASSPHRASE) or keyring (OS keyring, requires a build with -tags keyring); existing file tokens are migrated'"`
+}
+
+// CLI represents the command line interface configuration for dumping a server
+type CLI struct {
+	// Output options
+	Output string `kong:"short='o',help='Output file for documentation (defaults to stdout, required for hugo format as directory)'"`
+	Format string `kong:"short='f',default='markdown',enum='markdown,json,html,pdf,hugo',help='Output format'"`
+	NoTOC  bool   `kong:"help='Disable table of contents in markdown output'"`
+
+	// Frontmatter options
+	Frontmatter       bool     `kong:"short='F',help='Include frontmatter in markdown output (enabled by default for Hugo format)'"`
+	FrontmatterField  []string `kong:"short='M',help='Add custom frontmatter field (format: key:value), can be used multiple times'"`
+	FrontmatterFormat string   `kong:"default='yaml',enum='yaml,toml,json',help='Frontmatter format'"`
+
+	// Input options
+	Input string `kong:"short='i',help='Render a JSON dump saved with --format json instead of connecting to a server (- for stdin)'"`
+
+	// Connection options
+	ConnectionOptions `kong:"embed"`
+
+	// Context configuration
+	ContextFile []string `kong:"help='Path to context configuration files (YAML/JSON), can be used multiple times'"`
 
 	// Scanning options
 	NoTools     bool `kong:"help='Skip scanning tools from the MCP server'"`
 	NoResources bool `kong:"help='Skip scanning resources from the MCP server'"`
 	NoPrompts   bool `kong:"help='Skip scanning prompts from the MCP server'"`
 
+	// Pagination options
+	MaxPages    int `kong:"default='100',help='Maximum number of pages to fetch when listing tools, resources, or prompts'"`
+	MaxPageSize int `kong:"help='Maximum number of items to accept from a single list page (0 for unlimited)'"`
+
+	// Exit with an error when a section could not be collected
+	FailOnPartial bool `kong:"help='Exit with an error if tools, resources, or prompts could not be listed in full (output is still written, with a collection errors notice)'"`
+
+	// Resource reading options
+	ReadResources     bool     `kong:"help='Read resource contents into the output (text as code blocks, blobs as size/hash summaries)'"`
+	ResourcePattern   []string `kong:"help='Only read resources whose URI matches this glob pattern (implies --read-resources), can be used multiple times'"`
+	MaxResourceBytes  int      `kong:"default='65536',help='Maximum number of bytes of content to keep per resource'"`
+	ResourceMimeTypes []string `kong:"name='resource-mime-types',help='MIME types to read contents for (comma-separated, wildcards allowed, e.g. text/*,application/json); all types when empty'"`
+
+	// Prompt options
+	GetPrompts bool `kong:"help='Fetch each prompt with GetPrompt and include its messages (arguments from prompt_arguments in context files, otherwise generated)'"`
+
 	// Tool calling options
 	CallTool     []string `kong:"help='Call specific tool(s) by name, can be used multiple times'"`
 	ToolArgs     string   `kong:"help='JSON arguments for tool calls (applies to all --call-tool invocations)'"`
-	CallAllTools bool     `kong:"help='Call all available tools with empty arguments for testing'"`
+	CallAllTools bool     `kong:"help='Call all available tools with sample arguments generated from their input schemas (or --tool-args if given)'"`
+	SampleSeed   int64    `kong:"default='1',help='Seed for sample arguments generated by --call-all-tools (the same seed always produces the same arguments)'"`
+	CallsFile    string   `kong:"help='Path to a calls file (YAML/JSON) listing tool calls with per-call arguments, labels, and expected outcomes'"`
+	StrictCalls  bool     `kong:"help='Exit with an error if a tool call violates its declared schemas or expected outcome'"`
 
 	// Hugo-specific options (only used when format=hugo)
 	// Uses Hugo Modules with Presidium layouts
@@ -75,6 +172,11 @@ type CLI struct {
 	Args []string `kong:"arg,optional,help='Command and arguments (legacy format for backward compatibility)'"`
 }
 
+// Run executes the dump command. ctx is cancelled on SIGINT/SIGTERM.
+func (cli *CLI) Run(ctx context.Context) error {
+	return Run(ctx, cli)
+}
+
 // ValidateScanOptions validates that at least one scan type is enabled
 func (cli *CLI) ValidateScanOptions() error {
 	if cli.NoTools && cli.NoResources && cli.NoPrompts {
@@ -82,3 +184,36 @@ func (cli *CLI) ValidateScanOptions() error {
 	}
 	return nil
 }
+
+// ValidateInputOptions rejects flags that need a live server when rendering a saved dump with --input
+func (cli *CLI) ValidateInputOptions() error {
+	if cli.Input == "" {
+		return nil
+	}
+
+	liveOnly := []struct {
+		flag string
+		set  bool
+	}{
+		{"server command arguments", len(cli.Args) > 0},
+		{"--server-command", cli.ServerCommand != ""},
+		{"--endpoint", cli.Endpoint != ""},
+		{"--read-resources", cli.ReadResources},
+		{"--resource-pattern", len(cli.ResourcePattern) > 0},
+		{"--get-prompts", cli.GetPrompts},
+		{"--call-tool", len(cli.CallTool) > 0},
+		{"--call-all-tools", cli.CallAllTools},
+		{"--calls-file", cli.CallsFile != ""},
+	}
+
+	var conflicts []string
+	for _, option := range liveOnly {
+		if option.set {
+			conflicts = append(conflicts, option.flag)
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("--input renders a saved dump and cannot be combined with options that need a live server: %s", strings.Join(conflicts, ", "))
+	}
+	return nil
+}
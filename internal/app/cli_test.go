@@ -1,7 +1,13 @@
 package app
 
 import (
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/spandigital/mcp-server-dump/internal/configdir"
 )
 
 func TestCLI_ScanValidation(t *testing.T) {
@@ -138,3 +144,162 @@ func TestCLI_ScanFlags(t *testing.T) {
 		t.Errorf("Failed to set NoPrompts flag")
 	}
 }
+
+func TestCommands_Parse(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantCommand string
+		check       func(t *testing.T, cmds *Commands)
+	}{
+		{
+			name:        "legacy_form_defaults_to_dump",
+			args:        []string{"--format", "json", "node", "server.js"},
+			wantCommand: "dump <args>",
+			check: func(t *testing.T, cmds *Commands) {
+				if cmds.Dump.Format != "json" {
+					t.Errorf("Expected format json, got %q", cmds.Dump.Format)
+				}
+				if len(cmds.Dump.Args) != 2 || cmds.Dump.Args[0] != "node" || cmds.Dump.Args[1] != "server.js" {
+					t.Errorf("Expected server args [node server.js], got %v", cmds.Dump.Args)
+				}
+			},
+		},
+		{
+			name:        "explicit_dump_with_server_command",
+			args:        []string{"dump", "--server-command", "node server.js"},
+			wantCommand: "dump",
+			check: func(t *testing.T, cmds *Commands) {
+				if cmds.Dump.ServerCommand != "node server.js" {
+					t.Errorf("Expected server command to be set, got %q", cmds.Dump.ServerCommand)
+				}
+			},
+		},
+		{
+			name:        "diff_two_dumps",
+			args:        []string{"diff", "--fail-on", "breaking", "old.json", "new.json"},
+			wantCommand: "diff <baseline> <current>",
+			check: func(t *testing.T, cmds *Commands) {
+				if cmds.Diff.Baseline != "old.json" || cmds.Diff.Current != "new.json" {
+					t.Errorf("Unexpected diff arguments: %q %q", cmds.Diff.Baseline, cmds.Diff.Current)
+				}
+				if cmds.Diff.FailOn != FailOnBreaking {
+					t.Errorf("Expected fail-on breaking, got %q", cmds.Diff.FailOn)
+				}
+			},
+		},
+		{
+			name:        "diff_against_live_server",
+			args:        []string{"diff", "old.json", "--transport", "streamable", "--endpoint", "http://localhost:3001/mcp"},
+			wantCommand: "diff <baseline>",
+			check: func(t *testing.T, cmds *Commands) {
+				if cmds.Diff.Current != "" || cmds.Diff.Endpoint != "http://localhost:3001/mcp" {
+					t.Errorf("Expected live comparison against endpoint, got current=%q endpoint=%q", cmds.Diff.Current, cmds.Diff.Endpoint)
+				}
+			},
+		},
+		{
+			name:        "auth_logout_all",
+			args:        []string{"auth", "logout", "--all"},
+			wantCommand: "auth logout",
+			check: func(t *testing.T, cmds *Commands) {
+				if !cmds.Auth.Logout.All || cmds.Auth.Logout.Endpoint != "" {
+					t.Errorf("Expected logout --all, got %+v", cmds.Auth.Logout)
+				}
+			},
+		},
+		{
+			name:        "auth_login_with_oauth_flags",
+			args:        []string{"auth", "login", "https://mcp.example.com/mcp", "--oauth-client-id", "my-client", "--oauth-flow", "device"},
+			wantCommand: "auth login <endpoint>",
+			check: func(t *testing.T, cmds *Commands) {
+				login := cmds.Auth.Login
+				if login.Server != "https://mcp.example.com/mcp" || login.OAuthClientID != "my-client" || login.OAuthFlow != "device" {
+					t.Errorf("Unexpected login options: server=%q client=%q flow=%q", login.Server, login.OAuthClientID, login.OAuthFlow)
+				}
+			},
+		},
+		{
+			name:        "config_dir_after_subcommand",
+			args:        []string{"auth", "list", "--config-dir", "mcp-state", "--token-store", "encrypted"},
+			wantCommand: "auth list",
+			check: func(t *testing.T, cmds *Commands) {
+				t.Cleanup(func() { configdir.Set("") })
+				if !filepath.IsAbs(cmds.ConfigDir) || filepath.Base(cmds.ConfigDir) != "mcp-state" {
+					t.Errorf("Expected an absolute config dir, got %q", cmds.ConfigDir)
+				}
+				if dir, err := configdir.Dir(); err != nil || dir != cmds.ConfigDir {
+					t.Errorf("Expected the config dir to be applied, got %q, %v", dir, err)
+				}
+				if cmds.Auth.List.TokenStore != "encrypted" {
+					t.Errorf("Expected the encrypted token store, got %q", cmds.Auth.List.TokenStore)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cmds Commands
+			parser, err := kong.New(&cmds, kong.Vars{"version": "test"})
+			if err != nil {
+				t.Fatalf("Failed to create parser: %v", err)
+			}
+			ctx, err := parser.Parse(tt.args)
+			if err != nil {
+				t.Fatalf("Failed to parse %v: %v", tt.args, err)
+			}
+			if ctx.Command() != tt.wantCommand {
+				t.Errorf("Expected command %q, got %q", tt.wantCommand, ctx.Command())
+			}
+			tt.check(t, &cmds)
+		})
+	}
+}
+
+func TestCLI_ValidateInputOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cli     CLI
+		wantErr string
+	}{
+		{
+			name: "no_input",
+			cli:  CLI{GetPrompts: true, Args: []string{"node", "server.js"}},
+		},
+		{
+			name: "input_with_offline_options",
+			cli:  CLI{Input: "dump.json", Format: "html", ContextFile: []string{"context.yaml"}, NoPrompts: true},
+		},
+		{
+			name:    "input_with_server_args",
+			cli:     CLI{Input: "dump.json", Args: []string{"node", "server.js"}},
+			wantErr: "server command arguments",
+		},
+		{
+			name:    "input_with_live_only_flags",
+			cli:     CLI{Input: "dump.json", GetPrompts: true, CallAllTools: true},
+			wantErr: "--get-prompts, --call-all-tools",
+		},
+		{
+			name:    "input_with_endpoint",
+			cli:     CLI{Input: "dump.json", ConnectionOptions: ConnectionOptions{Endpoint: "http://localhost:3001/mcp"}},
+			wantErr: "--endpoint",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cli.ValidateInputOptions()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spandigital/mcp-server-dump/internal/diff"
+	"github.com/spandigital/mcp-server-dump/internal/model"
+)
+
+// Severity thresholds for --fail-on
+const (
+	FailOnNone     = "none"
+	FailOnBreaking = "breaking"
+	FailOnAny      = "any"
+)
+
+// DiffCmd compares a baseline JSON dump against a second dump or a live server
+type DiffCmd struct {
+	Baseline string `kong:"arg,help='Baseline JSON dump produced with --format json'"`
+	Current  string `kong:"arg,optional,help='Current JSON dump to compare (connects to a live server using the connection flags when omitted)'"`
+
+	// Output options
+	Output string `kong:"short='o',help='Output file for the report (defaults to stdout)'"`
+	Format string `kong:"short='f',default='markdown',enum='markdown,json',help='Report format'"`
+	FailOn string `kong:"default='none',enum='none,breaking,any',help='Exit with an error when changes of this severity are found (for CI)'"`
+
+	// Connection options, used when comparing against a live server
+	ConnectionOptions `kong:"embed"`
+}
+
+// Run executes the diff command. ctx is cancelled on SIGINT/SIGTERM.
+func (d *DiffCmd) Run(ctx context.Context) error {
+	baseline, err := model.LoadServerInfo(d.Baseline)
+	if err != nil {
+		return fmt.Errorf("failed to load baseline: %w", err)
+	}
+	if baseline.Partial() {
+		return fmt.Errorf("cannot compare against an incomplete baseline: %w", partialError(baseline))
+	}
+
+	current, err := d.loadCurrent(ctx)
+	if err != nil {
+		return err
+	}
+
+	report := diff.Compare(baseline, current)
+
+	var output []byte
+	switch d.Format {
+	case "json":
+		output, err = diff.FormatJSON(report)
+		if err != nil {
+			return fmt.Errorf("failed to format diff report: %w", err)
+		}
+	default:
+		output = diff.FormatMarkdown(report)
+	}
+
+	if err := writeOutput(output, d.Output); err != nil {
+		return err
+	}
+
+	// Fail after writing the report so CI logs still show what changed
+	return checkFailOn(report, d.FailOn)
+}
+
+// loadCurrent loads the current dump from a file, or collects it from a live server
+func (d *DiffCmd) loadCurrent(ctx context.Context) (*model.ServerInfo, error) {
+	if d.Current != "" {
+		current, err := model.LoadServerInfo(d.Current)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load current dump: %w", err)
+		}
+		if current.Partial() {
+			return nil, fmt.Errorf("cannot compare an incomplete dump: %w", partialError(current))
+		}
+		return current, nil
+	}
+
+	if d.Transport == "command" && d.ServerCommand == "" && d.ContainerImage == "" {
+		return nil, errors.New("either a current dump file or a server to connect to (--server-command, --container-image or --endpoint) is required")
+	}
+
+	session, transportName, closeSession, err := createMCPSession(ctx, &d.ConnectionOptions, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeSession()
+
+	info := collectServerInfo(ctx, session, &CLI{ConnectionOptions: d.ConnectionOptions})
+	info.Transport = transportName
+
+	// A section that failed to list, or was cut short by the page limit, would show up as
+	// every item past the failure being removed
+	if info.Partial() {
+		return nil, fmt.Errorf("cannot compare an incomplete dump: %w", partialError(info))
+	}
+	return info, nil
+}
+
+// checkFailOn returns an error if the report contains changes at or above the threshold
+func checkFailOn(report *diff.Report, failOn string) error {
+	switch {
+	case failOn == FailOnBreaking && report.HasBreaking():
+		return fmt.Errorf("found %d breaking change(s)", report.BreakingCount())
+	case failOn == FailOnAny && len(report.Changes) > 0:
+		return fmt.Errorf("found %d change(s), %d breaking", len(report.Changes), report.BreakingCount())
+	}
+	return nil
+}
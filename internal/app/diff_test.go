@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spandigital/mcp-server-dump/internal/model"
+)
+
+func TestDiffCmd_Run(t *testing.T) {
+	dir := t.TempDir()
+	writeDump := func(name string, info *model.ServerInfo) string {
+		t.Helper()
+		data, err := json.Marshal(info)
+		if err != nil {
+			t.Fatalf("Failed to encode dump: %v", err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatalf("Failed to write dump: %v", err)
+		}
+		return path
+	}
+
+	baseline := writeDump("baseline.json", &model.ServerInfo{
+		Name:  "test-server",
+		Tools: []model.Tool{{Name: "echo"}, {Name: "search"}},
+	})
+	current := writeDump("current.json", &model.ServerInfo{
+		Name:  "test-server",
+		Tools: []model.Tool{{Name: "echo", Description: "Echoes input"}},
+	})
+
+	tests := []struct {
+		name       string
+		failOn     string
+		format     string
+		wantErr    bool
+		wantOutput string
+	}{
+		{"report_only", FailOnNone, "markdown", false, "## Breaking Changes"},
+		{"fail_on_breaking", FailOnBreaking, "markdown", true, "`search`"},
+		{"fail_on_any", FailOnAny, "json", true, `"breaking": true`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputPath := filepath.Join(dir, tt.name+".out")
+			cmd := &DiffCmd{Baseline: baseline, Current: current, Output: outputPath, Format: tt.format, FailOn: tt.failOn}
+
+			err := cmd.Run(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error=%v, got %v", tt.wantErr, err)
+			}
+
+			// The report is written even when the command fails
+			output, readErr := os.ReadFile(outputPath)
+			if readErr != nil {
+				t.Fatalf("Failed to read report: %v", readErr)
+			}
+			if !strings.Contains(string(output), tt.wantOutput) {
+				t.Errorf("Expected report to contain %q, got:\n%s", tt.wantOutput, output)
+			}
+		})
+	}
+}
+
+func TestDiffCmd_RequiresCurrentOrServer(t *testing.T) {
+	dir := t.TempDir()
+	baseline := filepath.Join(dir, "baseline.json")
+	if err := os.WriteFile(baseline, []byte(`{"name": "test-server"}`), 0o600); err != nil {
+		t.Fatalf("Failed to write dump: %v", err)
+	}
+
+	cmd := &DiffCmd{Baseline: baseline, ConnectionOptions: ConnectionOptions{Transport: "command"}}
+	err := cmd.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "current dump file or a server") {
+		t.Errorf("Expected missing server error, got %v", err)
+	}
+}
+
+func TestDiffCmd_RefusesIncompleteDumps(t *testing.T) {
+	dir := t.TempDir()
+	complete := filepath.Join(dir, "complete.json")
+	truncated := filepath.Join(dir, "truncated.json")
+	failed := filepath.Join(dir, "failed.json")
+	for path, content := range map[string]string{
+		complete:  `{"name": "test-server", "tools": [{"name": "echo"}, {"name": "search"}]}`,
+		truncated: `{"name": "test-server", "tools": [{"name": "echo"}], "pagination": {"toolPages": 1, "truncated": ["tools"]}}`,
+		failed:    `{"name": "test-server", "collectionErrors": [{"section": "prompts", "error": "backend unavailable"}]}`,
+	} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write dump: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		baseline string
+		current  string
+		wantErr  string
+	}{
+		{"truncated_current", complete, truncated, "tools: listing truncated"},
+		{"truncated_baseline", truncated, complete, "incomplete baseline"},
+		{"failed_current", complete, failed, "prompts: backend unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputPath := filepath.Join(dir, tt.name+".out")
+			cmd := &DiffCmd{Baseline: tt.baseline, Current: tt.current, Output: outputPath, Format: "markdown", FailOn: FailOnNone}
+			err := cmd.Run(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected an incomplete dump error containing %q, got %v", tt.wantErr, err)
+			}
+			// No report is written, so a truncated section is never reported as removed
+			if _, statErr := os.Stat(outputPath); !os.IsNotExist(statErr) {
+				t.Errorf("Expected no report for an incomplete dump, got %v", statErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/spandigital/mcp-server-dump/internal/transport"
+)
+
+// Default pagination limits
+const (
+	// DefaultMaxPages is the default safety limit on the number of pages fetched per list call
+	DefaultMaxPages = 100
+)
+
+// pageFetcher fetches a single page of results for the given cursor.
+// It returns the items on the page and the cursor for the next page (empty when done).
+type pageFetcher[T any] func(ctx context.Context, cursor string) ([]T, string, error)
+
+// paginationOptions controls how cursor-paginated list calls are walked
+type paginationOptions struct {
+	// MaxPages is the maximum number of pages to fetch (0 means DefaultMaxPages)
+	MaxPages int
+	// MaxPageSize caps the number of items accepted from a single page (0 means unlimited)
+	MaxPageSize int
+	// Retry controls how a page that fails with a transient error is retried
+	Retry transport.RetryPolicy
+}
+
+// pageResult holds the outcome of walking a paginated list
+type pageResult[T any] struct {
+	Items     []T
+	Pages     int
+	Truncated bool
+	// Reason explains why the listing was truncated
+	Reason string
+}
+
+// newPaginationOptions builds pagination options from CLI configuration
+func newPaginationOptions(cli *CLI) paginationOptions {
+	return paginationOptions{
+		MaxPages:    cli.MaxPages,
+		MaxPageSize: cli.MaxPageSize,
+		Retry:       newRetryPolicy(&cli.ConnectionOptions),
+	}
+}
+
+// fetchAllPages walks every page of a cursor-paginated list until the server stops
+// returning a cursor or the page limit is reached. Items collected before an error
+// are returned alongside the error so callers can keep partial results. A page that fails
+// with a transient error is retried according to the retry policy.
+func fetchAllPages[T any](ctx context.Context, section string, opts paginationOptions, fetch pageFetcher[T]) (pageResult[T], error) {
+	maxPages := opts.MaxPages
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+
+	var result pageResult[T]
+	cursor := ""
+	seen := make(map[string]bool)
+
+	for {
+		var items []T
+		var nextCursor string
+		operation := fmt.Sprintf("Listing %s (page %d)", section, result.Pages+1)
+		err := opts.Retry.Do(ctx, operation, retryableError, func() error {
+			var fetchErr error
+			items, nextCursor, fetchErr = fetch(ctx, cursor)
+			return fetchErr
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Pages++
+
+		if opts.MaxPageSize > 0 && len(items) > opts.MaxPageSize {
+			log.Printf("Warning: %s page %d returned %d items, keeping the first %d (--max-page-size)", section, result.Pages, len(items), opts.MaxPageSize)
+			result.Truncated = true
+			result.Reason = fmt.Sprintf("page %d returned %d items, more than the --max-page-size of %d", result.Pages, len(items), opts.MaxPageSize)
+			items = items[:opts.MaxPageSize]
+		}
+		result.Items = append(result.Items, items...)
+
+		if nextCursor == "" {
+			return result, nil
+		}
+
+		// Guard against servers that hand back a cursor we've already followed
+		if seen[nextCursor] {
+			log.Printf("Warning: %s pagination returned a repeated cursor after %d pages, stopping", section, result.Pages)
+			result.Truncated = true
+			result.Reason = fmt.Sprintf("server repeated a pagination cursor after %d pages", result.Pages)
+			return result, nil
+		}
+		seen[nextCursor] = true
+
+		if result.Pages >= maxPages {
+			log.Printf("Warning: Reached page limit (%d) while listing %s, results may be incomplete (use --max-pages to raise it)", maxPages, section)
+			result.Truncated = true
+			result.Reason = fmt.Sprintf("reached the page limit of %d (use --max-pages to raise it)", maxPages)
+			return result, nil
+		}
+
+		cursor = nextCursor
+	}
+}
@@ -0,0 +1,169 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/spandigital/mcp-server-dump/internal/transport"
+)
+
+// fakePages returns a pageFetcher serving the given pages in order
+func fakePages(pages [][]string) pageFetcher[string] {
+	return func(_ context.Context, cursor string) ([]string, string, error) {
+		index := 0
+		if cursor != "" {
+			if _, err := fmt.Sscanf(cursor, "page-%d", &index); err != nil {
+				return nil, "", err
+			}
+		}
+		next := ""
+		if index+1 < len(pages) {
+			next = fmt.Sprintf("page-%d", index+1)
+		}
+		return pages[index], next, nil
+	}
+}
+
+func TestFetchAllPages(t *testing.T) {
+	tests := []struct {
+		name          string
+		pages         [][]string
+		opts          paginationOptions
+		wantItems     int
+		wantPages     int
+		wantTruncated bool
+	}{
+		{
+			name:      "single_page",
+			pages:     [][]string{{"a", "b"}},
+			wantItems: 2,
+			wantPages: 1,
+		},
+		{
+			name:      "multiple_pages",
+			pages:     [][]string{{"a", "b"}, {"c"}, {"d", "e"}},
+			wantItems: 5,
+			wantPages: 3,
+		},
+		{
+			name:          "page_limit_reached",
+			pages:         [][]string{{"a"}, {"b"}, {"c"}, {"d"}},
+			opts:          paginationOptions{MaxPages: 2},
+			wantItems:     2,
+			wantPages:     2,
+			wantTruncated: true,
+		},
+		{
+			name:          "page_size_cap",
+			pages:         [][]string{{"a", "b", "c"}, {"d", "e"}},
+			opts:          paginationOptions{MaxPageSize: 2},
+			wantItems:     4,
+			wantPages:     2,
+			wantTruncated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := fetchAllPages(context.Background(), "items", tt.opts, fakePages(tt.pages))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(result.Items) != tt.wantItems {
+				t.Errorf("Expected %d items, got %d", tt.wantItems, len(result.Items))
+			}
+			if result.Pages != tt.wantPages {
+				t.Errorf("Expected %d pages, got %d", tt.wantPages, result.Pages)
+			}
+			if result.Truncated != tt.wantTruncated {
+				t.Errorf("Expected truncated=%v, got %v", tt.wantTruncated, result.Truncated)
+			}
+		})
+	}
+}
+
+func TestFetchAllPages_RepeatedCursor(t *testing.T) {
+	calls := 0
+	fetch := func(_ context.Context, _ string) ([]string, string, error) {
+		calls++
+		return []string{"x"}, "same", nil
+	}
+
+	result, err := fetchAllPages(context.Background(), "items", paginationOptions{}, fetch)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls before detecting repeated cursor, got %d", calls)
+	}
+	if !result.Truncated {
+		t.Error("Expected result to be marked truncated")
+	}
+}
+
+func TestFetchAllPages_ErrorKeepsPartialResults(t *testing.T) {
+	fetchErr := errors.New("boom")
+	fetch := func(_ context.Context, cursor string) ([]string, string, error) {
+		if cursor == "" {
+			return []string{"a", "b"}, "next", nil
+		}
+		return nil, "", fetchErr
+	}
+
+	result, err := fetchAllPages(context.Background(), "items", paginationOptions{}, fetch)
+	if !errors.Is(err, fetchErr) {
+		t.Fatalf("Expected fetch error, got %v", err)
+	}
+	if len(result.Items) != 2 || result.Pages != 1 {
+		t.Errorf("Expected partial results (2 items, 1 page), got %d items, %d pages", len(result.Items), result.Pages)
+	}
+}
+
+func TestFetchAllPages_RetriesTransientErrors(t *testing.T) {
+	retry := transport.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	tests := []struct {
+		name      string
+		err       error
+		wantCalls int
+		wantErr   bool
+	}{
+		{"stream ended early", errors.New("unexpected EOF"), 2, false},
+		// The round tripper has already retried these
+		{"transport rejected the request", fmt.Errorf("calling tools/list: %w", &jsonrpc.Error{Code: -32005, Message: "Bad Gateway"}), 1, true},
+		{"http request failed", fmt.Errorf("sending tools/list: %w", &url.Error{Op: "Post", URL: "http://localhost", Err: errors.New("connection refused")}), 1, true},
+		{"server error", &jsonrpc.Error{Code: jsonrpc.CodeMethodNotFound, Message: "method not found"}, 1, true},
+		{"closed connection", fmt.Errorf("%w: calling tools/list", mcp.ErrConnectionClosed), 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			fetch := func(_ context.Context, _ string) ([]string, string, error) {
+				calls++
+				if calls == 1 {
+					return nil, "", tt.err
+				}
+				return []string{"a"}, "", nil
+			}
+
+			result, err := fetchAllPages(context.Background(), "items", paginationOptions{Retry: retry}, fetch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error=%v, got %v", tt.wantErr, err)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Expected %d calls, got %d", tt.wantCalls, calls)
+			}
+			if !tt.wantErr && len(result.Items) != 1 {
+				t.Errorf("Expected the retried page to be kept, got %v", result.Items)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"context"
+	"log"
+	"maps"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/spandigital/mcp-server-dump/internal/model"
+)
+
+// generatedArgumentPrefix prefixes placeholder values generated for required prompt arguments
+const generatedArgumentPrefix = "example_"
+
+// getPrompts fetches each prompt's messages with GetPrompt when requested and stores them in ServerInfo.
+// Failures are recorded on the individual prompt rather than aborting the dump.
+func getPrompts(session *mcp.ClientSession, ctx context.Context, info *model.ServerInfo, cli *CLI, contextConfig *model.ContextConfig) {
+	if !cli.GetPrompts {
+		return
+	}
+
+	if !info.Capabilities.Prompts || len(info.Prompts) == 0 {
+		log.Printf("Warning: Prompt fetching requested but server has no prompts capability or no prompts available")
+		return
+	}
+
+	for i := range info.Prompts {
+		prompt := &info.Prompts[i]
+		prompt.SampleArguments = promptArguments(prompt, contextConfig.PromptArgumentsFor(prompt.Name))
+
+		log.Printf("Getting prompt: %s", prompt.Name)
+		result, err := session.GetPrompt(ctx, &mcp.GetPromptParams{
+			Name:      prompt.Name,
+			Arguments: prompt.SampleArguments,
+		})
+		if err != nil {
+			prompt.GetError = err.Error()
+			log.Printf("Warning: Failed to get prompt %s: %v", prompt.Name, err)
+			continue
+		}
+
+		for _, message := range result.Messages {
+			prompt.Messages = append(prompt.Messages, convertPromptMessage(message))
+		}
+	}
+}
+
+// promptArguments builds the arguments for a GetPrompt call. Configured values are used as-is;
+// required arguments without a configured value get a generated placeholder.
+func promptArguments(prompt *model.Prompt, configured map[string]string) map[string]string {
+	args := make(map[string]string)
+	for _, arg := range prompt.ParsedArguments() {
+		if arg.Required {
+			args[arg.Name] = generatedArgumentPrefix + arg.Name
+		}
+	}
+	maps.Copy(args, configured)
+
+	if len(args) == 0 {
+		return nil
+	}
+	return args
+}
+
+// convertPromptMessage maps an SDK prompt message onto the model, flattening text content
+func convertPromptMessage(message *mcp.PromptMessage) model.PromptMessage {
+	converted := model.PromptMessage{Role: string(message.Role)}
+	if text, ok := message.Content.(*mcp.TextContent); ok {
+		converted.Text = text.Text
+	} else {
+		converted.Content = message.Content
+	}
+	return converted
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/spandigital/mcp-server-dump/internal/model"
+)
+
+func TestPromptArguments(t *testing.T) {
+	prompt := &model.Prompt{
+		Name: "analyze_code",
+		Arguments: []any{
+			&mcp.PromptArgument{Name: "language", Required: true},
+			&mcp.PromptArgument{Name: "code", Required: true},
+			// Arguments loaded from a JSON dump arrive as generic maps
+			map[string]any{"name": "focus", "required": false},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		configured map[string]string
+		want       map[string]string
+	}{
+		{
+			name: "generated_for_required",
+			want: map[string]string{"language": "example_language", "code": "example_code"},
+		},
+		{
+			name:       "configured_values_win",
+			configured: map[string]string{"language": "go", "focus": "security"},
+			want:       map[string]string{"language": "go", "code": "example_code", "focus": "security"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := promptArguments(prompt, tt.configured); !maps.Equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if got := promptArguments(&model.Prompt{Name: "no_args"}, nil); got != nil {
+		t.Errorf("Expected nil arguments for prompt without arguments, got %v", got)
+	}
+}
+
+func TestConvertPromptMessage(t *testing.T) {
+	text := convertPromptMessage(&mcp.PromptMessage{Role: "user", Content: &mcp.TextContent{Text: "Review this"}})
+	if text.Role != "user" || text.Text != "Review this" || text.Content != nil {
+		t.Errorf("Expected flattened text message, got %+v", text)
+	}
+
+	image := convertPromptMessage(&mcp.PromptMessage{Role: "assistant", Content: &mcp.ImageContent{MIMEType: "image/png"}})
+	if image.Text != "" || image.Content == nil {
+		t.Errorf("Expected non-text content to be kept, got %+v", image)
+	}
+}
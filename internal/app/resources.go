@@ -0,0 +1,169 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/spandigital/mcp-server-dump/internal/model"
+)
+
+// Fallback MIME types used when neither the content nor the listing declares one
+const (
+	defaultTextMimeType = "text/plain"
+	defaultBlobMimeType = "application/octet-stream"
+)
+
+// resourceReadOptions controls which resources are read and how much of their content is kept
+type resourceReadOptions struct {
+	// Patterns selects resources by URI glob (all listed resources when empty)
+	Patterns []string
+	// MaxBytes caps the stored content per resource (0 means unlimited)
+	MaxBytes int
+	// MimeTypes is the allow-list of MIME types whose contents are stored (all when empty)
+	MimeTypes []string
+}
+
+// newResourceReadOptions builds resource read options from CLI configuration
+func newResourceReadOptions(cli *CLI) resourceReadOptions {
+	return resourceReadOptions{
+		Patterns:  cli.ResourcePattern,
+		MaxBytes:  cli.MaxResourceBytes,
+		MimeTypes: cli.ResourceMimeTypes,
+	}
+}
+
+// readResources reads the contents of listed resources when requested and stores them in ServerInfo.
+// Read failures are recorded on the individual resource rather than aborting the dump.
+func readResources(session *mcp.ClientSession, ctx context.Context, info *model.ServerInfo, cli *CLI) {
+	if !cli.ReadResources && len(cli.ResourcePattern) == 0 {
+		return
+	}
+
+	if !info.Capabilities.Resources || len(info.Resources) == 0 {
+		log.Printf("Warning: Resource reading requested but server has no resources capability or no resources available")
+		return
+	}
+
+	opts := newResourceReadOptions(cli)
+	read := 0
+	for i := range info.Resources {
+		resource := &info.Resources[i]
+		if !opts.selects(resource.URI) {
+			continue
+		}
+		// Skip reads the allow-list would discard anyway; content without a listed type is checked once read
+		if resource.MimeType != "" && !opts.allowsMimeType(resource.MimeType) {
+			log.Printf("Skipping resource %s: MIME type %s is not in the allow-list", resource.URI, resource.MimeType)
+			continue
+		}
+
+		log.Printf("Reading resource: %s", resource.URI)
+		result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: resource.URI})
+		if err != nil {
+			resource.ReadError = err.Error()
+			log.Printf("Warning: Failed to read resource %s: %v", resource.URI, err)
+			continue
+		}
+
+		for _, contents := range result.Contents {
+			resource.Contents = append(resource.Contents, convertResourceContents(contents, resource.MimeType, opts))
+		}
+		read++
+	}
+
+	log.Printf("Read %d of %d resources", read, len(info.Resources))
+}
+
+// selects reports whether a resource URI matches the configured glob patterns
+func (o resourceReadOptions) selects(uri string) bool {
+	if len(o.Patterns) == 0 {
+		return true
+	}
+	for _, pattern := range o.Patterns {
+		if matched, err := path.Match(pattern, uri); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsMimeType reports whether the MIME type is on the allow-list, ignoring any parameters
+func (o resourceReadOptions) allowsMimeType(mimeType string) bool {
+	if len(o.MimeTypes) == 0 {
+		return true
+	}
+	mediaType, _, _ := strings.Cut(mimeType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	for _, allowed := range o.MimeTypes {
+		if matched, err := path.Match(strings.ToLower(strings.TrimSpace(allowed)), mediaType); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// convertResourceContents converts SDK resource contents into the model, applying the byte cap
+// and MIME allow-list. Size and hash always describe the full content returned by the server.
+func convertResourceContents(contents *mcp.ResourceContents, listedMimeType string, opts resourceReadOptions) model.ResourceContent {
+	isBlob := contents.Blob != nil
+	data := []byte(contents.Text)
+	if isBlob {
+		data = contents.Blob
+	}
+	sum := sha256.Sum256(data)
+
+	converted := model.ResourceContent{
+		URI:      contents.URI,
+		MimeType: contents.MIMEType,
+		IsBlob:   isBlob,
+		Size:     len(data),
+		SHA256:   hex.EncodeToString(sum[:]),
+	}
+	if converted.MimeType == "" {
+		converted.MimeType = listedMimeType
+	}
+
+	effectiveMimeType := converted.MimeType
+	if effectiveMimeType == "" {
+		effectiveMimeType = defaultTextMimeType
+		if isBlob {
+			effectiveMimeType = defaultBlobMimeType
+		}
+	}
+	if !opts.allowsMimeType(effectiveMimeType) {
+		converted.Skipped = "MIME type " + effectiveMimeType + " is not in the allow-list"
+		return converted
+	}
+
+	if !isBlob {
+		converted.Text, converted.Truncated = truncateUTF8(contents.Text, opts.MaxBytes)
+		return converted
+	}
+
+	// Partial binary content is of little use, so oversized blobs are summarised only
+	if opts.MaxBytes > 0 && len(contents.Blob) > opts.MaxBytes {
+		converted.Truncated = true
+		return converted
+	}
+	converted.Blob = contents.Blob
+	return converted
+}
+
+// truncateUTF8 shortens text to at most maxBytes without splitting a multi-byte character
+func truncateUTF8(text string, maxBytes int) (string, bool) {
+	if maxBytes <= 0 || len(text) <= maxBytes {
+		return text, false
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut], true
+}
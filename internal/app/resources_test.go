@@ -0,0 +1,173 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/spandigital/mcp-server-dump/internal/model"
+)
+
+func TestResourceReadOptions_Selects(t *testing.T) {
+	opts := resourceReadOptions{Patterns: []string{"file:///config/*.json", "memory://*"}}
+
+	tests := []struct {
+		uri  string
+		want bool
+	}{
+		{"file:///config/app.json", true},
+		{"file:///config/app.yaml", false},
+		{"file:///config/nested/app.json", false},
+		{"memory://session", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			if got := opts.selects(tt.uri); got != tt.want {
+				t.Errorf("selects(%q) = %v, want %v", tt.uri, got, tt.want)
+			}
+		})
+	}
+
+	if !(resourceReadOptions{}).selects("anything://at/all") {
+		t.Error("Expected empty pattern list to select every resource")
+	}
+}
+
+func TestResourceReadOptions_AllowsMimeType(t *testing.T) {
+	opts := resourceReadOptions{MimeTypes: []string{"text/*", "application/json"}}
+
+	tests := []struct {
+		mimeType string
+		want     bool
+	}{
+		{"text/plain", true},
+		{"text/markdown; charset=utf-8", true},
+		{"Application/JSON", true},
+		{"image/png", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mimeType, func(t *testing.T) {
+			if got := opts.allowsMimeType(tt.mimeType); got != tt.want {
+				t.Errorf("allowsMimeType(%q) = %v, want %v", tt.mimeType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertResourceContents(t *testing.T) {
+	t.Run("text_truncated", func(t *testing.T) {
+		contents := &mcp.ResourceContents{URI: "file:///a.txt", MIMEType: "text/plain", Text: strings.Repeat("a", 20)}
+		got := convertResourceContents(contents, "", resourceReadOptions{MaxBytes: 8})
+		if got.Text != "aaaaaaaa" || !got.Truncated {
+			t.Errorf("Expected 8 byte truncated text, got %q (truncated=%v)", got.Text, got.Truncated)
+		}
+		if got.Size != 20 {
+			t.Errorf("Expected size to describe full content (20), got %d", got.Size)
+		}
+	})
+
+	t.Run("oversized_blob_summarised", func(t *testing.T) {
+		contents := &mcp.ResourceContents{URI: "file:///a.bin", Blob: make([]byte, 32)}
+		got := convertResourceContents(contents, "application/octet-stream", resourceReadOptions{MaxBytes: 16})
+		if got.Blob != nil || !got.Truncated || !got.IsBlob {
+			t.Errorf("Expected blob to be summarised only, got %+v", got)
+		}
+		if got.SHA256 == "" || got.Size != 32 {
+			t.Errorf("Expected size and hash for blob, got size=%d hash=%q", got.Size, got.SHA256)
+		}
+	})
+
+	t.Run("mime_type_not_allowed", func(t *testing.T) {
+		contents := &mcp.ResourceContents{URI: "file:///a.png", Blob: []byte{1, 2, 3}}
+		got := convertResourceContents(contents, "image/png", resourceReadOptions{MimeTypes: []string{"text/*"}})
+		if got.Skipped == "" || got.Blob != nil {
+			t.Errorf("Expected content to be skipped, got %+v", got)
+		}
+		if got.MimeType != "image/png" {
+			t.Errorf("Expected listed MIME type fallback, got %q", got.MimeType)
+		}
+	})
+}
+
+func TestTruncateUTF8(t *testing.T) {
+	text := "héllo" // 'é' is two bytes
+	got, truncated := truncateUTF8(text, 2)
+	if got != "h" || !truncated {
+		t.Errorf("Expected truncation before multi-byte rune, got %q (truncated=%v)", got, truncated)
+	}
+
+	got, truncated = truncateUTF8(text, 0)
+	if got != text || truncated {
+		t.Errorf("Expected no truncation with zero limit, got %q (truncated=%v)", got, truncated)
+	}
+}
+
+func TestReadResources_SkipsExcludedMimeTypesBeforeReading(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var reads []string
+	handler := func(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		mu.Lock()
+		reads = append(reads, req.Params.URI)
+		mu.Unlock()
+		if req.Params.URI == "file:///notes" {
+			return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: req.Params.URI, Blob: []byte{0x00, 0x01}}}}, nil
+		}
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: req.Params.URI, Text: "hello"}}}, nil
+	}
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "resource-server", Version: "1.0.0"}, nil)
+	server.AddResource(&mcp.Resource{URI: "file:///readme.txt", Name: "readme", MIMEType: "text/plain"}, handler)
+	server.AddResource(&mcp.Resource{URI: "file:///logo.png", Name: "logo", MIMEType: "image/png"}, handler)
+	server.AddResource(&mcp.Resource{URI: "file:///notes", Name: "notes"}, handler)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	defer func() { _ = serverSession.Close() }()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	defer func() { _ = session.Close() }()
+
+	info := &model.ServerInfo{
+		Capabilities: model.Capabilities{Resources: true},
+		Resources: []model.Resource{
+			{URI: "file:///readme.txt", Name: "readme", MimeType: "text/plain"},
+			{URI: "file:///logo.png", Name: "logo", MimeType: "image/png"},
+			{URI: "file:///notes", Name: "notes"},
+		},
+	}
+	cli := &CLI{ReadResources: true, ResourceMimeTypes: []string{"text/*"}}
+
+	readResources(session, ctx, info, cli)
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, uri := range reads {
+		if uri == "file:///logo.png" {
+			t.Errorf("resource with excluded MIME type was read: %v", reads)
+		}
+	}
+	if len(reads) != 2 {
+		t.Errorf("reads = %v, want readme.txt and notes", reads)
+	}
+	if len(info.Resources[1].Contents) != 0 {
+		t.Errorf("excluded resource has contents: %+v", info.Resources[1].Contents)
+	}
+	// Blob contents without a listed type are still checked against the allow-list after reading
+	if got := info.Resources[2].Contents; len(got) != 1 || got[0].Skipped == "" {
+		t.Errorf("untyped resource contents = %+v, want skipped entry", got)
+	}
+}
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/spandigital/mcp-server-dump/internal/transport"
+)
+
+// newRetryPolicy builds the retry policy from the connection options
+func newRetryPolicy(conn *ConnectionOptions) transport.RetryPolicy {
+	return transport.RetryPolicy{
+		MaxAttempts:     conn.RetryAttempts,
+		InitialBackoff:  conn.RetryBackoff,
+		MaxBackoff:      conn.RetryMaxBackoff,
+		RetryableStatus: conn.RetryStatus,
+	}
+}
+
+// retryableError reports whether a failed connect or list call is worth retrying. Requests
+// sent through the HTTP client have already been retried by the transport's round tripper,
+// so errors from it, including requests the SDK rejected because of their HTTP status, are
+// final here. So are errors the server returned over JSON-RPC and a closed connection. What
+// is left are failures the round tripper cannot see, such as an event stream that ends early.
+func retryableError(err error) bool {
+	if errors.Is(err, mcp.ErrConnectionClosed) {
+		return false
+	}
+	var urlErr *url.Error
+	var rpcErr *jsonrpc.Error
+	return !errors.As(err, &urlErr) && !errors.As(err, &rpcErr)
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
@@ -20,139 +22,345 @@ const (
 	ErrAllScanTypesDisabled = "cannot disable all scan types: at least one of tools, resources, or prompts must be enabled"
 )
 
-// Run executes the main application logic
-func Run(cli *CLI) error {
+// Run executes the main application logic. Cancelling ctx stops collection and shuts the server down.
+func Run(ctx context.Context, cli *CLI) error {
 	// Validate that at least one scan type is enabled
 	if err := cli.ValidateScanOptions(); err != nil {
 		return err
 	}
+	if err := cli.ValidateInputOptions(); err != nil {
+		return err
+	}
 
-	ctx := context.Background()
-	session, err := createMCPSession(ctx, cli)
+	var info *model.ServerInfo
+	var err error
+	if cli.Input != "" {
+		info, err = loadDump(cli)
+	} else {
+		info, err = dumpServer(ctx, cli)
+	}
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if closeErr := session.Close(); closeErr != nil {
-			log.Printf("Warning: failed to close session: %v", closeErr)
+
+	output, err := formatOutput(info, cli)
+	if err != nil {
+		return err
+	}
+
+	if err := writeOutput(output, cli.Output); err != nil {
+		return err
+	}
+
+	// Fail after writing output so the report is still available to inspect
+	if cli.FailOnPartial && info.Partial() {
+		return partialError(info)
+	}
+	if cli.StrictCalls {
+		if failures := strictCallFailures(info.ToolCalls); len(failures) > 0 {
+			return fmt.Errorf("%d tool call(s) failed strict checks:\n  %s", len(failures), strings.Join(failures, "\n  "))
+		}
+	}
+
+	return nil
+}
+
+// partialError describes the sections that could not be collected
+func partialError(info *model.ServerInfo) error {
+	failures := make([]string, 0, len(info.CollectionErrors))
+	for _, collectionErr := range info.CollectionErrors {
+		failures = append(failures, fmt.Sprintf("%s: %s", collectionErr.Section, collectionErr.Error))
+	}
+	// Dumps can record a truncated section without a matching collection error
+	if info.Pagination != nil {
+		for _, section := range info.Pagination.Truncated {
+			if !slices.ContainsFunc(info.CollectionErrors, func(collectionErr model.CollectionError) bool { return collectionErr.Section == section }) {
+				failures = append(failures, fmt.Sprintf("%s: listing truncated", section))
+			}
 		}
-	}()
+	}
+	return fmt.Errorf("%d section(s) could not be collected:\n  %s", len(failures), strings.Join(failures, "\n  "))
+}
+
+// dumpServer connects to the MCP server and collects everything the CLI flags ask for
+func dumpServer(ctx context.Context, cli *CLI) (*model.ServerInfo, error) {
+	session, transportName, closeSession, err := createMCPSession(ctx, &cli.ConnectionOptions, cli.Args)
+	if err != nil {
+		return nil, err
+	}
+	defer closeSession()
 
-	info := collectServerInfo(session, cli)
+	info := collectServerInfo(ctx, session, cli)
+	info.Transport = transportName
 
-	if contextErr := applyContextConfig(info, cli.ContextFile); contextErr != nil {
-		return contextErr
+	contextConfig, contextErr := applyContextConfig(info, cli.ContextFile)
+	if contextErr != nil {
+		return nil, contextErr
 	}
 
+	// Read resource contents if requested
+	readResources(session, ctx, info, cli)
+
+	// Fetch prompt messages if requested
+	getPrompts(session, ctx, info, cli, contextConfig)
+
 	// Call tools if requested
 	if toolErr := callTools(session, ctx, info, cli); toolErr != nil {
-		return toolErr
+		return nil, toolErr
 	}
 
-	output, err := formatOutput(info, cli)
+	return info, nil
+}
+
+// loadDump reads a previously saved JSON dump in place of a live server, so documentation can
+// be re-rendered offline. Scan flags drop the matching sections and context files are applied
+// as they would be for a live server.
+func loadDump(cli *CLI) (*model.ServerInfo, error) {
+	info, err := model.LoadServerInfo(cli.Input)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	log.Printf("Loaded dump of %s from %s", info.Name, cli.Input)
+
+	if cli.NoTools {
+		log.Printf("Skipping tools from dump")
+		info.Tools = nil
+		info.ToolCalls = nil
 	}
+	if cli.NoResources {
+		log.Printf("Skipping resources from dump")
+		info.Resources = nil
+		info.ResourceTemplates = nil
+	}
+	if cli.NoPrompts {
+		log.Printf("Skipping prompts from dump")
+		info.Prompts = nil
+	}
+	dropSkippedCollectionErrors(info, cli)
 
-	return writeOutput(output, cli.Output)
+	if _, err := applyContextConfig(info, cli.ContextFile); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// dropSkippedCollectionErrors removes collection errors and truncation markers for sections
+// skipped by the scan flags
+func dropSkippedCollectionErrors(info *model.ServerInfo, cli *CLI) {
+	skipped := func(section string) bool {
+		switch section {
+		case "tools":
+			return cli.NoTools
+		case "resources", "resource templates":
+			return cli.NoResources
+		case "prompts":
+			return cli.NoPrompts
+		}
+		return false
+	}
+	info.CollectionErrors = slices.DeleteFunc(info.CollectionErrors, func(collectionErr model.CollectionError) bool {
+		return skipped(collectionErr.Section)
+	})
+	if info.Pagination != nil {
+		info.Pagination.Truncated = slices.DeleteFunc(info.Pagination.Truncated, skipped)
+	}
 }
 
 // createMCPSession establishes a connection to the MCP server using the configured transport.
-// It returns a client session for communicating with the server, or an error if connection fails.
+// It returns a client session for communicating with the server, the name of the transport in
+// use (the detected one for --transport auto) and a function that closes the session, or an
+// error if connection fails. Connection errors from the command transport include the
+// tail of the server's stderr.
 // The provided context allows for connection timeout and cancellation control.
+func createMCPSession(ctx context.Context, conn *ConnectionOptions, args []string) (*mcp.ClientSession, string, func(), error) {
+	transportConfig := newTransportConfig(conn, args)
+
+	ctx, oauthConfig, err := prepareOAuth(ctx, conn, &transportConfig)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	// Capture stderr from command servers so startup failures can be diagnosed
+	var stderr *serverStderr
+	if conn.Transport == "command" {
+		stderr, err = newServerStderr(conn)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		transportConfig.Stderr = stderr.writer()
+	}
+
+	mcpClient := mcp.NewClient(
+		&mcp.Implementation{
+			Name:    "mcp-server-dump",
+			Version: GetVersion(),
+		},
+		nil,
+	)
+
+	session, transportName, err := connectSession(ctx, mcpClient, &transportConfig, oauthConfig)
+	if err != nil {
+		// A failed connect has already stopped the server, so its stderr is complete
+		stderr.close()
+		return nil, "", nil, stderr.annotate(err)
+	}
+
+	closeSession := func() {
+		if closeErr := session.Close(); closeErr != nil {
+			log.Printf("Warning: failed to close session: %v", closeErr)
+		}
+		stderr.close()
+	}
+	return session, transportName, closeSession, nil
+}
+
+// newTransportConfig builds the transport configuration from the connection flags, with args
+// as the server command for the command transport
+func newTransportConfig(conn *ConnectionOptions, args []string) transport.Config {
+	return transport.Config{
+		Transport:     conn.Transport,
+		Endpoint:      conn.Endpoint,
+		Timeout:       conn.Timeout,
+		Headers:       conn.Headers,
+		ServerCommand: conn.ServerCommand,
+		Args:          args,
+		Env:           conn.Env,
+		EnvFiles:      conn.EnvFile,
+		ClearEnv:      conn.ClearEnv,
+		Cwd:           conn.Cwd,
+
+		ContainerImage:   conn.ContainerImage,
+		ContainerRuntime: conn.ContainerRuntime,
+		ContainerMounts:  conn.ContainerMount,
+		ContainerNetwork: conn.ContainerNetwork,
+
+		CACert:             conn.CACert,
+		ClientCert:         conn.ClientCert,
+		ClientKey:          conn.ClientKey,
+		TLSServerName:      conn.TLSServerName,
+		InsecureSkipVerify: conn.InsecureSkipVerify,
+
+		Proxy:      conn.Proxy,
+		UnixSocket: conn.UnixSocket,
+
+		Retry: newRetryPolicy(conn),
+	}
+}
+
+// prepareOAuth sets up OAuth for the connection. It returns a context whose OAuth requests use the
+// connection's TLS and network settings, and the OAuth configuration, which is nil when the
+// server does not require OAuth. Endpoints are discovered and a client is registered as needed.
 //
 //nolint:gocyclo // OAuth configuration logic requires multiple conditional branches
-func createMCPSession(ctx context.Context, cli *CLI) (*mcp.ClientSession, error) {
-	transportConfig := transport.Config{
-		Transport:     cli.Transport,
-		Endpoint:      cli.Endpoint,
-		Timeout:       cli.Timeout,
-		Headers:       cli.Headers,
-		ServerCommand: cli.ServerCommand,
-		Args:          cli.Args,
+func prepareOAuth(ctx context.Context, conn *ConnectionOptions, transportConfig *transport.Config) (context.Context, *auth.Config, error) {
+	// OAuth discovery and resource indicators use the HTTP form of WebSocket endpoints
+	endpoint := transport.HTTPEndpoint(conn.Endpoint)
+
+	// OAuth discovery, registration and token requests use the same TLS and network settings as the server
+	if conn.Transport != "command" {
+		if conn.InsecureSkipVerify {
+			log.Printf("Warning: TLS certificate verification is disabled (--insecure-skip-verify)")
+		}
+		httpTransport, err := transport.NewHTTPTransport(transportConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		ctx = auth.WithHTTPTransport(ctx, httpTransport)
+	}
+
+	clientKey, err := oauthClientKey(conn)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Create OAuth config if client ID is provided or if endpoint requires OAuth
 	var oauthConfig *auth.Config
-	if cli.OAuthClientID != "" {
-		// Validate that both auth and token URLs are provided if either is specified
-		if (cli.OAuthAuthURL != "" || cli.OAuthTokenURL != "") &&
-			(cli.OAuthAuthURL == "" || cli.OAuthTokenURL == "") {
-			return nil, fmt.Errorf("both --oauth-auth-url and --oauth-token-url must be provided together")
+	if conn.OAuthClientID != "" {
+		// Validate that both auth and token URLs are provided if either is specified.
+		// The client credentials grant only needs the token URL.
+		clientCredentials := auth.FlowType(conn.OAuthFlow) == auth.FlowTypeClientCredentials
+		if !clientCredentials && (conn.OAuthAuthURL != "" || conn.OAuthTokenURL != "") &&
+			(conn.OAuthAuthURL == "" || conn.OAuthTokenURL == "") {
+			return nil, nil, fmt.Errorf("both --oauth-auth-url and --oauth-token-url must be provided together")
 		}
 
-		// If auth/token URLs not provided, discover them automatically
+		// If the token URL is not provided, discover the endpoints automatically
 		var authURL, tokenURL string
-		if cli.OAuthAuthURL == "" && cli.OAuthTokenURL == "" {
-			fmt.Printf("Discovering OAuth endpoints from %s...\n", cli.Endpoint)
-			discoveredConfig, err := auth.DiscoverAndConfigure(ctx, cli.Endpoint)
+		if conn.OAuthTokenURL == "" {
+			// Progress goes to stderr: stdout carries the documentation output
+			log.Printf("Discovering OAuth endpoints from %s...", endpoint)
+			discoveredConfig, err := auth.DiscoverAndConfigure(ctx, endpoint)
 			if err != nil {
-				return nil, fmt.Errorf("failed to discover OAuth endpoints: %w", err)
+				return nil, nil, fmt.Errorf("failed to discover OAuth endpoints: %w", err)
 			}
 			if discoveredConfig == nil {
-				return nil, fmt.Errorf("server does not advertise OAuth endpoints")
+				return nil, nil, fmt.Errorf("server does not advertise OAuth endpoints")
 			}
 			authURL = discoveredConfig.AuthURL
 			tokenURL = discoveredConfig.TokenURL
-			fmt.Printf("✓ Discovered OAuth endpoints\n")
-			fmt.Printf("  Authorization URL: %s\n", authURL)
-			fmt.Printf("  Token URL: %s\n", tokenURL)
+			log.Printf("✓ Discovered OAuth endpoints")
+			log.Printf("  Authorization URL: %s", authURL)
+			log.Printf("  Token URL: %s", tokenURL)
 		} else {
 			// Use explicitly provided URLs
-			authURL = cli.OAuthAuthURL
-			tokenURL = cli.OAuthTokenURL
+			authURL = conn.OAuthAuthURL
+			tokenURL = conn.OAuthTokenURL
 		}
 
 		// Build OAuth configuration
 		oauthConfig = &auth.Config{
-			ClientID:     cli.OAuthClientID,
-			ClientSecret: cli.OAuthClientSecret,
-			Scopes:       cli.OAuthScopes,
-			RedirectPort: cli.OAuthRedirectPort,
-			ResourceURI:  cli.Endpoint, // MCP server endpoint is the resource URI
-			UseCache:     !cli.OAuthNoCache,
+			ClientID:     conn.OAuthClientID,
+			ClientSecret: conn.OAuthClientSecret,
+			Scopes:       conn.OAuthScopes,
+			RedirectPort: conn.OAuthRedirectPort,
+			ResourceURI:  endpoint, // MCP server endpoint is the resource URI
+			UseCache:     !conn.OAuthNoCache,
 			AuthURL:      authURL,
 			TokenURL:     tokenURL,
-			FlowType:     auth.FlowType(cli.OAuthFlow),
+			FlowType:     auth.FlowType(conn.OAuthFlow),
+
+			TokenEndpointAuthMethod: oauthAuthMethod(conn.OAuthAuthMethod),
+			ClientKey:               clientKey,
 		}
 
 		// If scopes not specified, use defaults
 		if len(oauthConfig.Scopes) == 0 {
 			oauthConfig.Scopes = auth.DefaultScopes()
 		}
-	} else if cli.Transport != "command" && cli.Endpoint != "" {
+	} else if conn.Transport != "command" && endpoint != "" {
 		// For HTTP transports without explicit OAuth config, try discovery + DCR
-		discoveredConfig, err := auth.DiscoverAndConfigure(ctx, cli.Endpoint)
+		discoveredConfig, err := auth.DiscoverAndConfigure(ctx, endpoint)
 		if err == nil && discoveredConfig != nil {
 			// OAuth required by server
-			fmt.Printf("OAuth required by server\n")
+			log.Printf("OAuth required by server")
 
 			// If endpoints not fully discovered, we'll try DCR or cached tokens
 			// Don't block here - let OAuth layer handle it
 			if discoveredConfig.AuthURL == "" && discoveredConfig.DeviceAuthURL == "" {
-				fmt.Printf("⚠️  Authorization endpoints not in .well-known metadata\n")
+				log.Printf("⚠️  Authorization endpoints not in .well-known metadata")
 				if discoveredConfig.UseDCR && discoveredConfig.RegistrationEndpoint != "" {
-					fmt.Printf("Will attempt Dynamic Client Registration...\n")
+					log.Printf("Will attempt Dynamic Client Registration...")
 				}
 			}
 
-			fmt.Printf("Discovered endpoints:\n")
+			log.Printf("Discovered endpoints:")
 			if discoveredConfig.AuthURL != "" {
-				fmt.Printf("  Authorization URL: %s\n", discoveredConfig.AuthURL)
+				log.Printf("  Authorization URL: %s", discoveredConfig.AuthURL)
 			}
 			if discoveredConfig.DeviceAuthURL != "" {
-				fmt.Printf("  Device Authorization URL: %s\n", discoveredConfig.DeviceAuthURL)
+				log.Printf("  Device Authorization URL: %s", discoveredConfig.DeviceAuthURL)
 			}
 			if discoveredConfig.TokenURL != "" {
-				fmt.Printf("  Token URL: %s\n", discoveredConfig.TokenURL)
+				log.Printf("  Token URL: %s", discoveredConfig.TokenURL)
 			}
 			if discoveredConfig.ClientID != "" {
-				fmt.Printf("  Client ID: %s (pre-configured)\n", discoveredConfig.ClientID)
+				log.Printf("  Client ID: %s (pre-configured)", discoveredConfig.ClientID)
 			}
 			if discoveredConfig.RegistrationEndpoint != "" {
-				fmt.Printf("  Registration Endpoint: %s\n", discoveredConfig.RegistrationEndpoint)
+				log.Printf("  Registration Endpoint: %s", discoveredConfig.RegistrationEndpoint)
 			}
-			fmt.Printf("  Flow Type: %s\n", discoveredConfig.FlowType)
+			log.Printf("  Flow Type: %s", discoveredConfig.FlowType)
 
 			// Determine how to obtain client credentials (priority order):
 			// 1. Use discovered pre-configured client ID if available
@@ -163,25 +371,26 @@ func createMCPSession(ctx context.Context, cli *CLI) (*mcp.ClientSession, error)
 			switch {
 			case discoveredConfig.ClientID != "":
 				// Use pre-configured client ID from discovery
-				fmt.Printf("✓ Using pre-configured client ID from server metadata\n")
+				log.Printf("✓ Using pre-configured client ID from server metadata")
 				clientID = discoveredConfig.ClientID
 				clientSecret = "" // Public client
 			case discoveredConfig.UseDCR && discoveredConfig.RegistrationEndpoint != "":
 				// Try DCR if no pre-configured client ID available
 				registration, regErr := auth.GetOrRegisterClient(
 					ctx,
-					cli.Endpoint,
+					endpoint,
 					discoveredConfig.RegistrationEndpoint,
 					discoveredConfig.Scopes,
+					clientKey,
 				)
 				if regErr != nil {
-					return nil, fmt.Errorf("failed to obtain client credentials via Dynamic Client Registration: %w", regErr)
+					return nil, nil, fmt.Errorf("failed to obtain client credentials via Dynamic Client Registration: %w", regErr)
 				}
 				clientID = registration.ClientID
 				clientSecret = registration.ClientSecret
 			default:
 				// Server requires OAuth but has no pre-configured client and doesn't support DCR
-				return nil, fmt.Errorf("OAuth authentication required but server does not provide a pre-configured client ID or support Dynamic Client Registration. Please provide --oauth-client-id")
+				return nil, nil, fmt.Errorf("OAuth authentication required but server does not provide a pre-configured client ID or support Dynamic Client Registration. Please provide --oauth-client-id")
 			}
 
 			// Build OAuth configuration with obtained client credentials
@@ -189,20 +398,23 @@ func createMCPSession(ctx context.Context, cli *CLI) (*mcp.ClientSession, error)
 				ClientID:             clientID,
 				ClientSecret:         clientSecret,
 				Scopes:               discoveredConfig.Scopes,
-				RedirectPort:         cli.OAuthRedirectPort,
-				ResourceURI:          cli.Endpoint,
-				UseCache:             !cli.OAuthNoCache,
+				RedirectPort:         conn.OAuthRedirectPort,
+				ResourceURI:          endpoint,
+				UseCache:             !conn.OAuthNoCache,
 				AuthURL:              discoveredConfig.AuthURL,
 				DeviceAuthURL:        discoveredConfig.DeviceAuthURL,
 				TokenURL:             discoveredConfig.TokenURL,
 				RegistrationEndpoint: discoveredConfig.RegistrationEndpoint,
 				FlowType:             discoveredConfig.FlowType, // Use discovered flow type
 				UseDCR:               discoveredConfig.UseDCR,
+
+				TokenEndpointAuthMethod: oauthAuthMethod(conn.OAuthAuthMethod),
+				ClientKey:               clientKey,
 			}
 
 			// Allow CLI flag to override discovered flow type if explicitly set
-			if cli.OAuthFlow != "" && cli.OAuthFlow != "auto" {
-				oauthConfig.FlowType = auth.FlowType(cli.OAuthFlow)
+			if conn.OAuthFlow != "" && conn.OAuthFlow != "auto" {
+				oauthConfig.FlowType = auth.FlowType(conn.OAuthFlow)
 			}
 
 			// If scopes not specified, use defaults
@@ -213,32 +425,89 @@ func createMCPSession(ctx context.Context, cli *CLI) (*mcp.ClientSession, error)
 		// If discovery fails or returns nil, proceed without OAuth
 	}
 
-	mcpTransport, err := transport.Create(&transportConfig, oauthConfig)
+	if oauthConfig != nil && oauthConfig.UseCache {
+		store, err := auth.NewTokenStore(conn.TokenStore)
+		if err != nil {
+			return nil, nil, err
+		}
+		oauthConfig.TokenStore = store
+	}
+
+	return ctx, oauthConfig, nil
+}
+
+// oauthAuthMethod maps --oauth-auth-method to the auth package value, where auto is empty
+func oauthAuthMethod(method string) string {
+	if method == "auto" {
+		return ""
+	}
+	return method
+}
+
+// oauthClientKey loads --oauth-private-key for private_key_jwt client authentication, or returns nil
+func oauthClientKey(conn *ConnectionOptions) (*auth.ClientKey, error) {
+	if conn.OAuthPrivateKey == "" {
+		if conn.OAuthAuthMethod == auth.AuthMethodPrivateKeyJWT {
+			return nil, fmt.Errorf("--oauth-auth-method=private_key_jwt requires --oauth-private-key")
+		}
+		return nil, nil
+	}
+	if conn.OAuthAuthMethod == auth.AuthMethodClientSecretBasic || conn.OAuthAuthMethod == auth.AuthMethodClientSecretPost {
+		return nil, fmt.Errorf("--oauth-private-key cannot be used with --oauth-auth-method=%s", conn.OAuthAuthMethod)
+	}
+
+	key, err := auth.LoadClientKey(conn.OAuthPrivateKey, conn.OAuthKeyID, conn.OAuthSigningAlg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create transport: %w", err)
+		return nil, fmt.Errorf("invalid --oauth-private-key: %w", err)
 	}
+	return key, nil
+}
 
-	mcpClient := mcp.NewClient(
-		&mcp.Implementation{
-			Name:    "mcp-server-dump",
-			Version: GetVersion(),
-		},
-		nil,
-	)
+// connectSession creates the configured transport and initializes a session over it,
+// letting the transport package pick streamable or SSE for --transport auto. Connecting
+// over HTTP is retried on transient failures; a command server that fails to start is not.
+func connectSession(ctx context.Context, client *mcp.Client, config *transport.Config, oauthConfig *auth.Config) (*mcp.ClientSession, string, error) {
+	policy := config.Retry
+	if config.Transport == "command" {
+		policy.MaxAttempts = 1
+	}
 
-	session, err := mcpClient.Connect(ctx, mcpTransport, nil)
+	var session *mcp.ClientSession
+	transportName := config.Transport
+
+	if config.Transport == "auto" {
+		err := policy.Do(ctx, "Connecting to MCP server", retryableError, func() error {
+			var connErr error
+			session, transportName, connErr = transport.ConnectAuto(ctx, client, config, oauthConfig)
+			return connErr
+		})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to connect to MCP server: %w", err)
+		}
+		return session, transportName, nil
+	}
+
+	mcpTransport, err := transport.Create(config, oauthConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to MCP server: %w", err)
+		return nil, "", fmt.Errorf("failed to create transport: %w", err)
 	}
 
-	return session, nil
+	// The transport is reused across attempts so an OAuth token obtained once is kept
+	err = policy.Do(ctx, "Connecting to MCP server", retryableError, func() error {
+		var connErr error
+		session, connErr = client.Connect(ctx, mcpTransport, nil)
+		return connErr
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to connect to MCP server: %w", err)
+	}
+	return session, transportName, nil
 }
 
 // collectServerInfo gathers basic server information and capabilities from the MCP server.
 // It initializes the server info structure with name, version, and capability flags.
 // The CLI flags control which types of data are actually collected.
-func collectServerInfo(session *mcp.ClientSession, cli *CLI) *model.ServerInfo {
-	ctx := context.Background()
+func collectServerInfo(ctx context.Context, session *mcp.ClientSession, cli *CLI) *model.ServerInfo {
 	initResult := session.InitializeResult()
 
 	info := &model.ServerInfo{
@@ -249,21 +518,25 @@ func collectServerInfo(session *mcp.ClientSession, cli *CLI) *model.ServerInfo {
 			Resources: initResult.Capabilities.Resources != nil,
 			Prompts:   initResult.Capabilities.Prompts != nil,
 		},
+		Pagination: &model.Pagination{},
 	}
 
+	pageOpts := newPaginationOptions(cli)
+
 	// Conditionally collect data based on CLI flags
 	if !cli.NoTools {
-		collectTools(session, ctx, initResult, info)
+		collectTools(session, ctx, initResult, info, pageOpts)
 	} else {
 		log.Printf("Skipping tools collection")
 	}
 	if !cli.NoResources {
-		collectResources(session, ctx, initResult, info)
+		collectResources(session, ctx, initResult, info, pageOpts)
+		collectResourceTemplates(session, ctx, initResult, info, pageOpts)
 	} else {
 		log.Printf("Skipping resources collection")
 	}
 	if !cli.NoPrompts {
-		collectPrompts(session, ctx, initResult, info)
+		collectPrompts(session, ctx, initResult, info, pageOpts)
 	} else {
 		log.Printf("Skipping prompts collection")
 	}
@@ -271,40 +544,77 @@ func collectServerInfo(session *mcp.ClientSession, cli *CLI) *model.ServerInfo {
 	return info
 }
 
-// collectTools retrieves and processes tools from the MCP server
-func collectTools(session *mcp.ClientSession, ctx context.Context, initResult *mcp.InitializeResult, info *model.ServerInfo) {
+// collectTools retrieves and processes tools from the MCP server, following pagination cursors
+func collectTools(session *mcp.ClientSession, ctx context.Context, initResult *mcp.InitializeResult, info *model.ServerInfo, pageOpts paginationOptions) {
 	if initResult.Capabilities.Tools == nil {
 		return
 	}
 
-	toolsList, err := session.ListTools(ctx, &mcp.ListToolsParams{})
+	result, err := fetchAllPages(ctx, "tools", pageOpts, func(ctx context.Context, cursor string) ([]*mcp.Tool, string, error) {
+		page, err := session.ListTools(ctx, &mcp.ListToolsParams{Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return page.Tools, page.NextCursor, nil
+	})
+	recordPages(info, "tools", result)
 	if err != nil {
 		log.Printf("Warning: Failed to list tools: %v", err)
-		return
+		info.AddCollectionError("tools", err)
 	}
 
-	for _, tool := range toolsList.Tools {
-		info.Tools = append(info.Tools, model.Tool{
-			Name:        tool.Name,
-			Description: tool.Description,
-			InputSchema: tool.InputSchema,
-		})
+	for _, tool := range result.Items {
+		info.Tools = append(info.Tools, convertTool(tool))
+	}
+}
+
+// convertTool maps an SDK tool onto the model, carrying over its title, output schema and annotations
+func convertTool(tool *mcp.Tool) model.Tool {
+	converted := model.Tool{
+		Name:         tool.Name,
+		Title:        tool.Title,
+		Description:  tool.Description,
+		InputSchema:  tool.InputSchema,
+		OutputSchema: tool.OutputSchema,
+	}
+
+	if tool.Annotations != nil {
+		converted.Annotations = &model.ToolAnnotations{
+			Title:           tool.Annotations.Title,
+			ReadOnlyHint:    tool.Annotations.ReadOnlyHint,
+			DestructiveHint: tool.Annotations.DestructiveHint,
+			IdempotentHint:  tool.Annotations.IdempotentHint,
+			OpenWorldHint:   tool.Annotations.OpenWorldHint,
+		}
+		// The specification prefers Tool.Title, falling back to the annotation title
+		if converted.Title == "" {
+			converted.Title = tool.Annotations.Title
+		}
 	}
+
+	return converted
 }
 
-// collectResources retrieves and processes resources from the MCP server
-func collectResources(session *mcp.ClientSession, ctx context.Context, initResult *mcp.InitializeResult, info *model.ServerInfo) {
+// collectResources retrieves and processes resources from the MCP server, following pagination cursors
+func collectResources(session *mcp.ClientSession, ctx context.Context, initResult *mcp.InitializeResult, info *model.ServerInfo, pageOpts paginationOptions) {
 	if initResult.Capabilities.Resources == nil {
 		return
 	}
 
-	resourcesList, err := session.ListResources(ctx, &mcp.ListResourcesParams{})
+	result, err := fetchAllPages(ctx, "resources", pageOpts, func(ctx context.Context, cursor string) ([]*mcp.Resource, string, error) {
+		page, err := session.ListResources(ctx, &mcp.ListResourcesParams{Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return page.Resources, page.NextCursor, nil
+	})
+	recordPages(info, "resources", result)
 	if err != nil {
 		log.Printf("Warning: Failed to list resources: %v", err)
-		return
+		info.AddCollectionError("resources", err)
 	}
 
-	for _, resource := range resourcesList.Resources {
+	for _, resource := range result.Items {
 		info.Resources = append(info.Resources, model.Resource{
 			URI:         resource.URI,
 			Name:        resource.Name,
@@ -314,19 +624,55 @@ func collectResources(session *mcp.ClientSession, ctx context.Context, initResul
 	}
 }
 
-// collectPrompts retrieves and processes prompts from the MCP server
-func collectPrompts(session *mcp.ClientSession, ctx context.Context, initResult *mcp.InitializeResult, info *model.ServerInfo) {
+// collectResourceTemplates retrieves parameterized resource templates from the MCP server, following pagination cursors
+func collectResourceTemplates(session *mcp.ClientSession, ctx context.Context, initResult *mcp.InitializeResult, info *model.ServerInfo, pageOpts paginationOptions) {
+	if initResult.Capabilities.Resources == nil {
+		return
+	}
+
+	result, err := fetchAllPages(ctx, "resource templates", pageOpts, func(ctx context.Context, cursor string) ([]*mcp.ResourceTemplate, string, error) {
+		page, err := session.ListResourceTemplates(ctx, &mcp.ListResourceTemplatesParams{Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return page.ResourceTemplates, page.NextCursor, nil
+	})
+	recordPages(info, "resource templates", result)
+	if err != nil {
+		log.Printf("Warning: Failed to list resource templates: %v", err)
+		info.AddCollectionError("resource templates", err)
+	}
+
+	for _, template := range result.Items {
+		info.ResourceTemplates = append(info.ResourceTemplates, model.ResourceTemplate{
+			URITemplate: template.URITemplate,
+			Name:        template.Name,
+			Description: template.Description,
+			MimeType:    template.MIMEType,
+		})
+	}
+}
+
+// collectPrompts retrieves and processes prompts from the MCP server, following pagination cursors
+func collectPrompts(session *mcp.ClientSession, ctx context.Context, initResult *mcp.InitializeResult, info *model.ServerInfo, pageOpts paginationOptions) {
 	if initResult.Capabilities.Prompts == nil {
 		return
 	}
 
-	promptsList, err := session.ListPrompts(ctx, &mcp.ListPromptsParams{})
+	result, err := fetchAllPages(ctx, "prompts", pageOpts, func(ctx context.Context, cursor string) ([]*mcp.Prompt, string, error) {
+		page, err := session.ListPrompts(ctx, &mcp.ListPromptsParams{Cursor: cursor})
+		if err != nil {
+			return nil, "", err
+		}
+		return page.Prompts, page.NextCursor, nil
+	})
+	recordPages(info, "prompts", result)
 	if err != nil {
 		log.Printf("Warning: Failed to list prompts: %v", err)
-		return
+		info.AddCollectionError("prompts", err)
 	}
 
-	for _, prompt := range promptsList.Prompts {
+	for _, prompt := range result.Items {
 		var args []any
 		for _, arg := range prompt.Arguments {
 			args = append(args, arg)
@@ -339,16 +685,41 @@ func collectPrompts(session *mcp.ClientSession, ctx context.Context, initResult
 	}
 }
 
+// recordPages stores the number of pages fetched for a section. A truncated listing is also
+// recorded as a collection error, so the output is marked incomplete and --fail-on-partial fails.
+func recordPages[T any](info *model.ServerInfo, section string, result pageResult[T]) {
+	pages := result.Pages
+	if info.Pagination == nil {
+		info.Pagination = &model.Pagination{}
+	}
+
+	switch section {
+	case "tools":
+		info.Pagination.ToolPages = pages
+	case "resources":
+		info.Pagination.ResourcePages = pages
+	case "resource templates":
+		info.Pagination.ResourceTemplatePages = pages
+	case "prompts":
+		info.Pagination.PromptPages = pages
+	}
+
+	if result.Truncated {
+		info.Pagination.Truncated = append(info.Pagination.Truncated, section)
+		info.AddCollectionError(section, fmt.Errorf("listing truncated: %s", result.Reason))
+	}
+}
+
 // applyContextConfig applies context enhancement configuration from external YAML/JSON files.
 // It merges context data to enrich tool, resource, and prompt descriptions with additional content.
-func applyContextConfig(info *model.ServerInfo, contextFiles []string) error {
+func applyContextConfig(info *model.ServerInfo, contextFiles []string) (*model.ContextConfig, error) {
 	if len(contextFiles) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	contextConfig, err := model.LoadContextConfig(contextFiles)
 	if err != nil {
-		return fmt.Errorf("failed to load context configuration: %w", err)
+		return nil, fmt.Errorf("failed to load context configuration: %w", err)
 	}
 
 	for i := range info.Tools {
@@ -357,11 +728,14 @@ func applyContextConfig(info *model.ServerInfo, contextFiles []string) error {
 	for i := range info.Resources {
 		contextConfig.ApplyToResource(&info.Resources[i])
 	}
+	for i := range info.ResourceTemplates {
+		contextConfig.ApplyToResourceTemplate(&info.ResourceTemplates[i])
+	}
 	for i := range info.Prompts {
 		contextConfig.ApplyToPrompt(&info.Prompts[i])
 	}
 
-	return nil
+	return contextConfig, nil
 }
 
 // formatOutput converts the server information into the requested output format (markdown, HTML, JSON, PDF, or Hugo).
@@ -464,78 +838,137 @@ func writeOutput(output []byte, outputPath string) error {
 }
 
 // callTools calls MCP tools based on CLI configuration and stores results in ServerInfo.
-// It supports calling specific tools by name or all available tools.
+// It supports calling specific tools by name, all available tools, or the calls listed in a calls file.
 func callTools(session *mcp.ClientSession, ctx context.Context, info *model.ServerInfo, cli *CLI) error {
 	// Skip if no tool calling is requested
-	if len(cli.CallTool) == 0 && !cli.CallAllTools {
+	if len(cli.CallTool) == 0 && !cli.CallAllTools && cli.CallsFile == "" {
 		return nil
 	}
 
+	calls, err := planToolCalls(info, cli)
+	if err != nil {
+		return err
+	}
+
 	// Skip if tools capability is not available
 	if !info.Capabilities.Tools || len(info.Tools) == 0 {
-		if cli.CallAllTools || len(cli.CallTool) > 0 {
-			log.Printf("Warning: Tool calling requested but server has no tools capability or no tools available")
-		}
+		log.Printf("Warning: Tool calling requested but server has no tools capability or no tools available")
 		return nil
 	}
 
+	toolsByName := make(map[string]*model.Tool, len(info.Tools))
+	for i := range info.Tools {
+		toolsByName[info.Tools[i].Name] = &info.Tools[i]
+	}
+
+	// Call each tool, validate against its declared schemas, and collect results
+	for _, call := range calls {
+		result := callSingleTool(session, ctx, call)
+		validateToolCall(toolsByName[call.Tool], &result)
+		info.ToolCalls = append(info.ToolCalls, result)
+	}
+
+	return nil
+}
+
+// planToolCalls builds the ordered list of tool calls from --call-tool/--call-all-tools
+// (sharing the global --tool-args) followed by the entries of the calls file
+func planToolCalls(info *model.ServerInfo, cli *CLI) ([]model.ToolCallSpec, error) {
 	// Parse tool arguments if provided
 	var args any
 	if cli.ToolArgs != "" {
 		if err := json.Unmarshal([]byte(cli.ToolArgs), &args); err != nil {
-			return fmt.Errorf("failed to parse tool arguments: %w", err)
+			return nil, fmt.Errorf("failed to parse tool arguments: %w", err)
 		}
 	}
 
-	// Determine which tools to call
-	var toolsToCall []string
+	var calls []model.ToolCallSpec
 	if cli.CallAllTools {
-		// Call all available tools
+		// Call all available tools, generating arguments from each input schema unless --tool-args was given
 		for _, tool := range info.Tools {
-			toolsToCall = append(toolsToCall, tool.Name)
+			toolArgs := args
+			if cli.ToolArgs == "" {
+				toolArgs = sampleArgumentsFor(tool, cli.SampleSeed)
+			}
+			calls = append(calls, model.ToolCallSpec{Tool: tool.Name, Arguments: toolArgs})
 		}
-		log.Printf("Calling all %d available tools", len(toolsToCall))
+		log.Printf("Calling all %d available tools", len(info.Tools))
 	} else {
 		// Call specific tools
-		toolsToCall = cli.CallTool
+		for _, toolName := range cli.CallTool {
+			calls = append(calls, model.ToolCallSpec{Tool: toolName, Arguments: args})
+		}
 	}
 
-	// Call each tool and collect results
-	for _, toolName := range toolsToCall {
-		result := callSingleTool(session, ctx, toolName, args)
-		info.ToolCalls = append(info.ToolCalls, result)
+	if cli.CallsFile != "" {
+		callsConfig, err := model.LoadCallsFile(cli.CallsFile)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Loaded %d tool calls from %s", len(callsConfig.Calls), cli.CallsFile)
+		calls = append(calls, callsConfig.Calls...)
 	}
 
-	return nil
+	return calls, nil
+}
+
+// sampleArgumentsFor generates sample arguments for a tool, falling back to empty arguments
+// if its input schema cannot be processed
+func sampleArgumentsFor(tool model.Tool, seed int64) any {
+	args, err := generateSampleArguments(tool.Name, tool.InputSchema, seed)
+	if err != nil {
+		log.Printf("Warning: Failed to generate sample arguments for %s, using empty arguments: %v", tool.Name, err)
+		return map[string]any{}
+	}
+	return args
 }
 
 // callSingleTool calls a single tool and returns the result.
 // It handles errors gracefully by storing them in the ToolCall result.
-func callSingleTool(session *mcp.ClientSession, ctx context.Context, toolName string, args any) model.ToolCall {
-	log.Printf("Calling tool: %s", toolName)
+func callSingleTool(session *mcp.ClientSession, ctx context.Context, call model.ToolCallSpec) model.ToolCall {
+	if call.Label != "" {
+		log.Printf("Calling tool: %s (%s)", call.Tool, call.Label)
+	} else {
+		log.Printf("Calling tool: %s", call.Tool)
+	}
 
 	result := model.ToolCall{
-		ToolName:  toolName,
-		Arguments: args,
+		ToolName:        call.Tool,
+		Label:           call.Label,
+		Arguments:       call.Arguments,
+		ExpectedOutcome: call.Expect,
 	}
 
 	callResult, err := session.CallTool(ctx, &mcp.CallToolParams{
-		Name:      toolName,
-		Arguments: args,
+		Name:      call.Tool,
+		Arguments: call.Arguments,
 	})
 	if err != nil {
 		result.Error = err.Error()
-		log.Printf("Warning: Tool call failed for %s: %v", toolName, err)
-		return result
-	}
-
-	// Store the content and structured content from the result
-	if callResult != nil {
+		log.Printf("Warning: Tool call failed for %s: %v", call.Tool, err)
+	} else if callResult != nil {
+		// Store the content and structured content from the result
 		for _, content := range callResult.Content {
 			result.Content = append(result.Content, content)
 		}
 		result.StructuredContent = callResult.StructuredContent
+		result.IsError = callResult.IsError
 	}
 
+	checkExpectedOutcome(&result)
 	return result
 }
+
+// checkExpectedOutcome flags calls whose outcome differs from the expectation in the calls file
+func checkExpectedOutcome(result *model.ToolCall) {
+	switch result.ExpectedOutcome {
+	case model.OutcomeSuccess:
+		result.OutcomeMismatch = result.Failed()
+	case model.OutcomeError:
+		result.OutcomeMismatch = !result.Failed()
+	}
+
+	if result.OutcomeMismatch {
+		log.Printf("Warning: Tool call %s did not produce the expected outcome (%s)", result.ToolName, result.ExpectedOutcome)
+	}
+}
@@ -1,7 +1,22 @@
 package app
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/spandigital/mcp-server-dump/internal/configdir"
+	"github.com/spandigital/mcp-server-dump/internal/model"
 )
 
 func TestRunValidation_ScanControls(t *testing.T) {
@@ -142,3 +157,325 @@ func TestCLI_ScanFlagCombinations(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertTool_Annotations(t *testing.T) {
+	destructive := false
+	tool := &mcp.Tool{
+		Name:         "write_note",
+		Description:  "Writes a note",
+		InputSchema:  map[string]any{"type": "object"},
+		OutputSchema: map[string]any{"type": "object"},
+		Annotations: &mcp.ToolAnnotations{
+			Title:           "Write Note",
+			DestructiveHint: &destructive,
+			IdempotentHint:  true,
+		},
+	}
+
+	converted := convertTool(tool)
+
+	if converted.Title != "Write Note" {
+		t.Errorf("Expected title to fall back to annotation title, got %q", converted.Title)
+	}
+	if converted.OutputSchema == nil {
+		t.Error("Expected output schema to be carried over")
+	}
+	if converted.Annotations == nil {
+		t.Fatal("Expected annotations to be carried over")
+	}
+	if converted.Annotations.IsDestructive() {
+		t.Error("Expected explicit destructiveHint=false to be preserved")
+	}
+	if !converted.Annotations.IsOpenWorld() {
+		t.Error("Expected omitted openWorldHint to default to true")
+	}
+
+	tool.Title = "Explicit Title"
+	if got := convertTool(tool).Title; got != "Explicit Title" {
+		t.Errorf("Expected Tool.Title to take precedence, got %q", got)
+	}
+}
+
+func TestPlanToolCalls(t *testing.T) {
+	info := &model.ServerInfo{Tools: []model.Tool{{Name: "a"}, {Name: "b"}}}
+
+	callsFile := filepath.Join(t.TempDir(), "calls.yaml")
+	content := `
+calls:
+  - tool: a
+    label: first
+    arguments: {x: 1}
+  - tool: a
+    label: second
+    arguments: {x: 2}
+    expect: error
+`
+	if err := os.WriteFile(callsFile, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write calls file: %v", err)
+	}
+
+	calls, err := planToolCalls(info, &CLI{CallTool: []string{"b"}, ToolArgs: `{"y":true}`, CallsFile: callsFile})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(calls) != 3 {
+		t.Fatalf("Expected 3 calls, got %d", len(calls))
+	}
+	if calls[0].Tool != "b" || calls[0].Arguments == nil {
+		t.Errorf("Expected --call-tool entry with global arguments first, got %+v", calls[0])
+	}
+	if calls[1].Label != "first" || calls[2].Label != "second" || calls[2].Expect != model.OutcomeError {
+		t.Errorf("Expected calls file entries in order, got %+v", calls[1:])
+	}
+
+	if _, err := planToolCalls(info, &CLI{CallAllTools: true, ToolArgs: "{invalid"}); err == nil {
+		t.Error("Expected error for invalid --tool-args")
+	}
+}
+
+func TestCheckExpectedOutcome(t *testing.T) {
+	tests := []struct {
+		name         string
+		call         model.ToolCall
+		wantMismatch bool
+	}{
+		{"no_expectation", model.ToolCall{Error: "boom"}, false},
+		{"success_met", model.ToolCall{ExpectedOutcome: model.OutcomeSuccess}, false},
+		{"success_missed_by_tool_error", model.ToolCall{ExpectedOutcome: model.OutcomeSuccess, IsError: true}, true},
+		{"error_met", model.ToolCall{ExpectedOutcome: model.OutcomeError, Error: "boom"}, false},
+		{"error_missed", model.ToolCall{ExpectedOutcome: model.OutcomeError}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call := tt.call
+			checkExpectedOutcome(&call)
+			if call.OutcomeMismatch != tt.wantMismatch {
+				t.Errorf("Expected mismatch=%v, got %v", tt.wantMismatch, call.OutcomeMismatch)
+			}
+		})
+	}
+}
+
+func TestRun_Input(t *testing.T) {
+	dir := t.TempDir()
+
+	dump := &model.ServerInfo{
+		Name:    "saved-server",
+		Version: "1.2.3",
+		Tools:   []model.Tool{{Name: "echo", Description: "Echoes input", InputSchema: map[string]any{"type": "object"}}},
+		Prompts: []model.Prompt{{Name: "review", Description: "Reviews code"}},
+	}
+	data, err := json.Marshal(dump)
+	if err != nil {
+		t.Fatalf("Failed to encode dump: %v", err)
+	}
+	dumpPath := filepath.Join(dir, "dump.json")
+	if err := os.WriteFile(dumpPath, data, 0o600); err != nil {
+		t.Fatalf("Failed to write dump: %v", err)
+	}
+
+	contextPath := filepath.Join(dir, "context.yaml")
+	contextYAML := "contexts:\n  tools:\n    echo:\n      usage: \"Offline usage notes\"\n"
+	if err := os.WriteFile(contextPath, []byte(contextYAML), 0o600); err != nil {
+		t.Fatalf("Failed to write context file: %v", err)
+	}
+
+	outputPath := filepath.Join(dir, "out.md")
+	cli := &CLI{
+		Input:       dumpPath,
+		Output:      outputPath,
+		Format:      "markdown",
+		ContextFile: []string{contextPath},
+		NoPrompts:   true,
+	}
+	if err := Run(context.Background(), cli); err != nil {
+		t.Fatalf("Run with --input failed: %v", err)
+	}
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	for _, want := range []string{"saved-server", "echo", "Offline usage notes"} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("Expected output to contain %q", want)
+		}
+	}
+	if strings.Contains(string(output), "Reviews code") {
+		t.Error("Expected prompts to be dropped by --no-prompts")
+	}
+}
+
+func TestRun_AutoTransportRecordedInOutput(t *testing.T) {
+	t.Setenv(configdir.HomeEnv, t.TempDir())
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "legacy-server", Version: "0.1.0"}, nil)
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", mcp.NewSSEHandler(func(*http.Request) *mcp.Server { return server }, nil))
+	httpServer := httptest.NewServer(mux)
+	defer httpServer.Close()
+
+	outputPath := filepath.Join(t.TempDir(), "dump.json")
+	cli := &CLI{
+		Output: outputPath,
+		Format: "json",
+		ConnectionOptions: ConnectionOptions{
+			Transport: "auto",
+			Endpoint:  httpServer.URL + "/mcp",
+			Timeout:   5 * time.Second,
+		},
+	}
+	if err := Run(context.Background(), cli); err != nil {
+		t.Fatalf("Run with --transport auto failed: %v", err)
+	}
+
+	info, err := model.LoadServerInfo(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to load output: %v", err)
+	}
+	if info.Transport != "sse" {
+		t.Errorf("Expected detected transport sse in output, got %q", info.Transport)
+	}
+}
+
+func TestRun_CollectionErrors(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "flaky-server", Version: "1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "echo", Description: "Echoes input"},
+		func(context.Context, *mcp.CallToolRequest, struct{}) (*mcp.CallToolResult, any, error) {
+			return &mcp.CallToolResult{}, nil, nil
+		})
+	server.AddPrompt(&mcp.Prompt{Name: "review", Description: "Reviews code"},
+		func(context.Context, *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return &mcp.GetPromptResult{}, nil
+		})
+	server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method == "tools/list" {
+				return nil, errors.New("tools backend unavailable")
+			}
+			return next(ctx, method, req)
+		}
+	})
+	httpServer := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+	defer httpServer.Close()
+
+	conn := ConnectionOptions{Transport: "streamable", Endpoint: httpServer.URL, Timeout: 5 * time.Second}
+
+	t.Run("notice in output", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "out.md")
+		if err := Run(context.Background(), &CLI{Output: outputPath, Format: "markdown", ConnectionOptions: conn}); err != nil {
+			t.Fatalf("Run without --fail-on-partial failed: %v", err)
+		}
+		output, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("Failed to read output: %v", err)
+		}
+		for _, want := range []string{"Collection errors", "**tools:**", "tools backend unavailable", "review"} {
+			if !strings.Contains(string(output), want) {
+				t.Errorf("Expected output to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("fail on partial", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "dump.json")
+		err := Run(context.Background(), &CLI{Output: outputPath, Format: "json", FailOnPartial: true, ConnectionOptions: conn})
+		if err == nil || !strings.Contains(err.Error(), "tools: ") {
+			t.Fatalf("Expected --fail-on-partial to fail the run, got %v", err)
+		}
+
+		// The report is still written for inspection
+		info, err := model.LoadServerInfo(outputPath)
+		if err != nil {
+			t.Fatalf("Failed to load output: %v", err)
+		}
+		if len(info.CollectionErrors) != 1 || info.CollectionErrors[0].Section != "tools" {
+			t.Errorf("Expected a tools collection error, got %+v", info.CollectionErrors)
+		}
+		if len(info.Prompts) != 1 {
+			t.Errorf("Expected prompts to be collected, got %d", len(info.Prompts))
+		}
+	})
+}
+
+func TestRun_PageLimitIsPartial(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "paged-server", Version: "1.0.0"}, &mcp.ServerOptions{PageSize: 1})
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		mcp.AddTool(server, &mcp.Tool{Name: name, Description: "Tool " + name},
+			func(context.Context, *mcp.CallToolRequest, struct{}) (*mcp.CallToolResult, any, error) {
+				return &mcp.CallToolResult{}, nil, nil
+			})
+	}
+	httpServer := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+	defer httpServer.Close()
+
+	conn := ConnectionOptions{Transport: "streamable", Endpoint: httpServer.URL, Timeout: 5 * time.Second}
+
+	t.Run("notice in output", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "out.md")
+		if err := Run(context.Background(), &CLI{Output: outputPath, Format: "markdown", MaxPages: 2, ConnectionOptions: conn}); err != nil {
+			t.Fatalf("Run without --fail-on-partial failed: %v", err)
+		}
+		output, err := os.ReadFile(outputPath)
+		if err != nil {
+			t.Fatalf("Failed to read output: %v", err)
+		}
+		for _, want := range []string{"Collection errors", "**tools:**", "page limit of 2"} {
+			if !strings.Contains(string(output), want) {
+				t.Errorf("Expected output to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("fail on partial", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "dump.json")
+		err := Run(context.Background(), &CLI{Output: outputPath, Format: "json", MaxPages: 2, FailOnPartial: true, ConnectionOptions: conn})
+		if err == nil || !strings.Contains(err.Error(), "tools: listing truncated") {
+			t.Fatalf("Expected --fail-on-partial to fail a page-limited run, got %v", err)
+		}
+
+		info, err := model.LoadServerInfo(outputPath)
+		if err != nil {
+			t.Fatalf("Failed to load output: %v", err)
+		}
+		if len(info.Tools) != 2 || info.Pagination == nil || !slices.Equal(info.Pagination.Truncated, []string{"tools"}) {
+			t.Errorf("Expected 2 tools and a truncated tools section, got %d tools, %+v", len(info.Tools), info.Pagination)
+		}
+	})
+}
+
+func TestRun_PageSizeCapIsPartial(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "bulky-server", Version: "1.0.0"}, nil)
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		mcp.AddTool(server, &mcp.Tool{Name: name, Description: "Tool " + name},
+			func(context.Context, *mcp.CallToolRequest, struct{}) (*mcp.CallToolResult, any, error) {
+				return &mcp.CallToolResult{}, nil, nil
+			})
+	}
+	httpServer := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+	defer httpServer.Close()
+
+	outputPath := filepath.Join(t.TempDir(), "out.md")
+	err := Run(context.Background(), &CLI{
+		Output:            outputPath,
+		Format:            "markdown",
+		MaxPageSize:       2,
+		FailOnPartial:     true,
+		ConnectionOptions: ConnectionOptions{Transport: "streamable", Endpoint: httpServer.URL, Timeout: 5 * time.Second},
+	})
+	if err == nil || !strings.Contains(err.Error(), "--max-page-size of 2") {
+		t.Fatalf("Expected --fail-on-partial to fail when items are dropped, got %v", err)
+	}
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	for _, want := range []string{"Collection errors", "**tools:**", "returned 3 items"} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("Expected output to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,406 @@
+package app
+
+import (
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spandigital/mcp-server-dump/internal/model"
+)
+
+// maxSampleDepth stops generation from following recursive schemas forever
+const maxSampleDepth = 8
+
+// sampleFormats holds fixed, valid values for common JSON Schema string formats
+var sampleFormats = map[string]string{
+	"date-time": "2024-01-01T00:00:00Z",
+	"date":      "2024-01-01",
+	"time":      "00:00:00Z",
+	"duration":  "PT1H",
+	"email":     "user@example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"uri":       "https://example.com",
+	"url":       "https://example.com",
+}
+
+// sampleGenerator synthesises minimal values that satisfy a JSON Schema.
+// Values are derived from a seeded source so the same seed always yields the same arguments.
+type sampleGenerator struct {
+	root map[string]any
+	rng  *rand.Rand
+}
+
+// generateSampleArguments builds a minimal argument object for a tool's input schema.
+// The per-tool source mixes the seed with the tool name so adding or removing tools
+// does not change the arguments generated for the others.
+func generateSampleArguments(toolName string, schema any, seed int64) (any, error) {
+	root, err := model.SchemaMap(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(toolName))
+	gen := &sampleGenerator{
+		root: root,
+		rng:  rand.New(rand.NewPCG(uint64(seed), hash.Sum64())), // #nosec G404 - deterministic sample data, not security sensitive
+	}
+
+	value := gen.generate(root, 0)
+	if value == nil {
+		// Tool arguments must be an object, even for tools without parameters
+		return map[string]any{}, nil
+	}
+	return value, nil
+}
+
+// generate returns a value satisfying the schema, preferring values the schema itself provides
+func (g *sampleGenerator) generate(schema map[string]any, depth int) any {
+	if depth > maxSampleDepth {
+		return nil
+	}
+	schema = g.resolve(schema, depth)
+
+	if value, ok := schema["const"]; ok {
+		return value
+	}
+	if value, ok := schema["default"]; ok {
+		return value
+	}
+	if examples, ok := schema["examples"].([]any); ok && len(examples) > 0 {
+		return examples[0]
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		return enum[g.rng.IntN(len(enum))]
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		return g.generateObject(schema, depth)
+	case "array":
+		return g.generateArray(schema, depth)
+	case "string":
+		return g.generateString(schema)
+	case "integer":
+		return g.generateInteger(schema)
+	case "number":
+		return g.generateNumber(schema)
+	case "boolean":
+		return g.rng.IntN(2) == 1
+	default:
+		return nil
+	}
+}
+
+// resolve follows local $ref pointers and collapses allOf/anyOf/oneOf into a single schema
+func (g *sampleGenerator) resolve(schema map[string]any, depth int) map[string]any {
+	if ref, ok := schema["$ref"].(string); ok {
+		if target := g.lookupRef(ref); target != nil && depth < maxSampleDepth {
+			return g.resolve(target, depth+1)
+		}
+	}
+
+	for _, key := range []string{"anyOf", "oneOf"} {
+		if branches, ok := schema[key].([]any); ok && len(branches) > 0 {
+			if first, ok := branches[0].(map[string]any); ok {
+				return g.resolve(first, depth+1)
+			}
+		}
+	}
+
+	if branches, ok := schema["allOf"].([]any); ok {
+		merged := make(map[string]any)
+		for key, value := range schema {
+			if key != "allOf" {
+				merged[key] = value
+			}
+		}
+		for _, branch := range branches {
+			if branchSchema, ok := branch.(map[string]any); ok {
+				mergeSchemas(merged, g.resolve(branchSchema, depth+1))
+			}
+		}
+		return merged
+	}
+
+	return schema
+}
+
+// lookupRef resolves a local JSON pointer such as "#/$defs/address" against the root schema
+func (g *sampleGenerator) lookupRef(ref string) map[string]any {
+	pointer, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil
+	}
+
+	var current any = g.root
+	for part := range strings.SplitSeq(strings.TrimPrefix(pointer, "/"), "/") {
+		if part == "" {
+			continue
+		}
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = m[part]
+	}
+
+	target, _ := current.(map[string]any)
+	return target
+}
+
+// mergeSchemas merges an allOf branch into the combined schema, unioning properties and required fields
+func mergeSchemas(into, from map[string]any) {
+	for key, value := range from {
+		switch key {
+		case "properties":
+			// Clone rather than modify in place: the maps belong to the tool's schema
+			existing, _ := into["properties"].(map[string]any)
+			props := maps.Clone(existing)
+			if props == nil {
+				props = make(map[string]any)
+			}
+			if fromProps, ok := value.(map[string]any); ok {
+				maps.Copy(props, fromProps)
+			}
+			into["properties"] = props
+		case "required":
+			existing, _ := into["required"].([]any)
+			if fromRequired, ok := value.([]any); ok {
+				into["required"] = slices.Concat(existing, fromRequired)
+			}
+		default:
+			if _, exists := into[key]; !exists {
+				into[key] = value
+			}
+		}
+	}
+}
+
+// schemaType returns the schema's type, picking the first non-null entry of a type array
+// and inferring object or array from structural keywords when the type is omitted
+func schemaType(schema map[string]any) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []any:
+		for _, entry := range t {
+			if s, ok := entry.(string); ok && s != "null" {
+				return s
+			}
+		}
+		return "null"
+	}
+
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	if _, ok := schema["items"]; ok {
+		return "array"
+	}
+	return ""
+}
+
+// generateObject fills in only the required properties, keeping the arguments minimal
+func (g *sampleGenerator) generateObject(schema map[string]any, depth int) map[string]any {
+	obj := make(map[string]any)
+	props, _ := schema["properties"].(map[string]any)
+	required, _ := schema["required"].([]any)
+
+	for _, entry := range required {
+		name, ok := entry.(string)
+		if !ok {
+			continue
+		}
+		propSchema, _ := props[name].(map[string]any)
+		if propSchema == nil {
+			propSchema = map[string]any{"type": "string"}
+		}
+		obj[name] = g.generate(propSchema, depth+1)
+	}
+
+	return obj
+}
+
+// generateArray produces the minimum number of items the schema requires
+func (g *sampleGenerator) generateArray(schema map[string]any, depth int) []any {
+	count := intKeyword(schema, "minItems", 0)
+	items, _ := schema["items"].(map[string]any)
+	if items == nil {
+		items = map[string]any{"type": "string"}
+	}
+
+	arr := make([]any, 0, count)
+	for range count {
+		arr = append(arr, g.generate(items, depth+1))
+	}
+	return arr
+}
+
+// generateString produces a value for a known format, or a short token padded to the length bounds
+func (g *sampleGenerator) generateString(schema map[string]any) string {
+	format, _ := schema["format"].(string)
+	if value, ok := sampleFormats[format]; ok {
+		return value
+	}
+	if format == "uuid" {
+		return fmt.Sprintf("%08x-%04x-4%03x-8%03x-%012x", g.rng.Uint32(), g.rng.IntN(0x10000), g.rng.IntN(0x1000), g.rng.IntN(0x1000), g.rng.Uint64()&0xffffffffffff)
+	}
+
+	value := fmt.Sprintf("sample-%d", g.rng.IntN(1000))
+	if minLength := intKeyword(schema, "minLength", 0); len(value) < minLength {
+		value += strings.Repeat("x", minLength-len(value))
+	}
+	if maxLength := intKeyword(schema, "maxLength", -1); maxLength >= 0 && len(value) > maxLength {
+		value = value[:maxLength]
+	}
+	return value
+}
+
+// numericRange is the range of values allowed by a numeric schema
+type numericRange struct {
+	low, high                   float64
+	exclusiveLow, exclusiveHigh bool
+}
+
+// generateInteger picks an integer within the schema's bounds that honours multipleOf
+func (g *sampleGenerator) generateInteger(schema map[string]any) int64 {
+	r := numericBounds(schema)
+	lo := int64(math.Ceil(r.low))
+	hi := int64(math.Floor(r.high))
+	if r.exclusiveLow && float64(lo) == r.low {
+		lo++
+	}
+	if r.exclusiveHigh && float64(hi) == r.high {
+		hi--
+	}
+	if hi < lo {
+		return lo
+	}
+
+	step := int64(1)
+	if multipleOf, ok := schema["multipleOf"].(float64); ok && multipleOf > 0 {
+		// The integers that are multiples of a fractional step, such as 2.5, are the
+		// multiples of its smallest whole multiple, 5
+		if whole, found := wholeMultiple(multipleOf); found {
+			step = whole
+		}
+	}
+
+	first := ceilDiv(lo, step)
+	last := floorDiv(hi, step)
+	if last < first {
+		return lo // No multiple lies within the bounds
+	}
+	return (first + g.rng.Int64N(last-first+1)) * step
+}
+
+// generateNumber picks a number within the schema's bounds, using whole numbers for
+// readability, or the smallest allowed multiple when multipleOf is set
+func (g *sampleGenerator) generateNumber(schema map[string]any) float64 {
+	step, ok := schema["multipleOf"].(float64)
+	if !ok || step <= 0 {
+		return float64(g.generateInteger(schema))
+	}
+
+	r := numericBounds(schema)
+	first := math.Ceil(r.low / step)
+	if r.exclusiveLow && roundToStep(first*step, step) <= r.low {
+		first++
+	}
+	last := math.Floor(r.high / step)
+	if r.exclusiveHigh && roundToStep(last*step, step) >= r.high {
+		last--
+	}
+	if last < first {
+		return r.low // No multiple lies within the bounds
+	}
+	return roundToStep(first*step, step)
+}
+
+// wholeMultiple returns the smallest whole-number multiple of step, if there is a small one
+func wholeMultiple(step float64) (int64, bool) {
+	for n := 1.0; n <= 1000; n++ {
+		if whole := math.Round(step * n); whole >= 1 && math.Abs(step*n-whole) < 1e-9 {
+			return int64(whole), true
+		}
+	}
+	return 0, false
+}
+
+// ceilDiv and floorDiv divide a by a positive b, rounding towards positive and negative infinity
+func ceilDiv(a, b int64) int64 {
+	return -floorDiv(-a, b)
+}
+
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+// roundToStep removes the floating-point noise from a multiple of step, such as 0.30000000000000004
+// for 3 * 0.1, by rounding it to the number of decimal places in step
+func roundToStep(value, step float64) float64 {
+	places := 0
+	if text := strconv.FormatFloat(step, 'f', -1, 64); strings.Contains(text, ".") {
+		places = len(text) - strings.Index(text, ".") - 1
+	}
+	rounded, err := strconv.ParseFloat(strconv.FormatFloat(value, 'f', places, 64), 64)
+	if err != nil {
+		return value
+	}
+	return rounded
+}
+
+// numericBounds returns the range declared by a numeric schema, supporting both the
+// numeric (2019+) and boolean (draft-04) forms of exclusiveMinimum/exclusiveMaximum.
+// Unbounded sides default to a span of 100 so generated values stay small.
+func numericBounds(schema map[string]any) numericRange {
+	const span = 100
+	var r numericRange
+
+	low, hasLow := schema["minimum"].(float64)
+	if v, ok := schema["exclusiveMinimum"].(float64); ok {
+		low, hasLow, r.exclusiveLow = v, true, true
+	} else if exclusive, _ := schema["exclusiveMinimum"].(bool); exclusive && hasLow {
+		r.exclusiveLow = true
+	}
+
+	high, hasHigh := schema["maximum"].(float64)
+	if v, ok := schema["exclusiveMaximum"].(float64); ok {
+		high, hasHigh, r.exclusiveHigh = v, true, true
+	} else if exclusive, _ := schema["exclusiveMaximum"].(bool); exclusive && hasHigh {
+		r.exclusiveHigh = true
+	}
+
+	switch {
+	case hasLow && !hasHigh:
+		high = low + span
+	case hasHigh && !hasLow:
+		low = min(0, high-span)
+	case !hasLow && !hasHigh:
+		high = span
+	}
+
+	r.low, r.high = low, high
+	return r
+}
+
+// intKeyword reads a non-negative integer keyword such as minItems, returning fallback if absent
+func intKeyword(schema map[string]any, key string, fallback int) int {
+	if v, ok := schema[key].(float64); ok && v >= 0 {
+		return int(v)
+	}
+	return fallback
+}
@@ -0,0 +1,224 @@
+package app
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateSampleArguments(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema map[string]any
+		want   map[string]any
+	}{
+		{
+			name:   "no_schema_gives_empty_object",
+			schema: nil,
+			want:   map[string]any{},
+		},
+		{
+			name: "only_required_properties",
+			schema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"location": map[string]any{"type": "string", "default": "London"},
+					"units":    map[string]any{"type": "string"},
+				},
+				"required": []any{"location"},
+			},
+			want: map[string]any{"location": "London"},
+		},
+		{
+			name: "const_examples_and_formats",
+			schema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"kind":  map[string]any{"const": "fixed"},
+					"query": map[string]any{"type": "string", "examples": []any{"weather"}},
+					"when":  map[string]any{"type": "string", "format": "date-time"},
+					"email": map[string]any{"type": "string", "format": "email"},
+				},
+				"required": []any{"kind", "query", "when", "email"},
+			},
+			want: map[string]any{"kind": "fixed", "query": "weather", "when": "2024-01-01T00:00:00Z", "email": "user@example.com"},
+		},
+		{
+			name: "nested_objects_arrays_and_refs",
+			schema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"address": map[string]any{"$ref": "#/$defs/address"},
+					"tags":    map[string]any{"type": "array", "items": map[string]any{"const": "tag"}, "minItems": float64(2)},
+				},
+				"required": []any{"address", "tags"},
+				"$defs": map[string]any{
+					"address": map[string]any{
+						"type":       "object",
+						"properties": map[string]any{"country": map[string]any{"enum": []any{"ZA"}}},
+						"required":   []any{"country"},
+					},
+				},
+			},
+			want: map[string]any{"address": map[string]any{"country": "ZA"}, "tags": []any{"tag", "tag"}},
+		},
+		{
+			name: "all_of_merges_required",
+			schema: map[string]any{
+				"allOf": []any{
+					map[string]any{"type": "object", "properties": map[string]any{"a": map[string]any{"const": 1.0}}, "required": []any{"a"}},
+					map[string]any{"properties": map[string]any{"b": map[string]any{"type": "null"}}, "required": []any{"b"}},
+				},
+			},
+			want: map[string]any{"a": 1.0, "b": nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := generateSampleArguments("tool", tt.schema, 1)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %#v, got %#v", tt.want, got)
+			}
+			if tt.schema != nil {
+				if err := validateAgainstSchema(tt.schema, got); err != nil {
+					t.Errorf("Generated arguments do not validate against the schema: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateSampleArguments_Deterministic(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"id":    map[string]any{"type": "string", "format": "uuid"},
+			"count": map[string]any{"type": "integer"},
+			"flag":  map[string]any{"type": "boolean"},
+			"name":  map[string]any{"type": "string"},
+		},
+		"required": []any{"id", "count", "flag", "name"},
+	}
+
+	first, _ := generateSampleArguments("tool", schema, 42)
+	second, _ := generateSampleArguments("tool", schema, 42)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected identical arguments for the same seed, got %v and %v", first, second)
+	}
+
+	other, _ := generateSampleArguments("other_tool", schema, 42)
+	if reflect.DeepEqual(first, other) {
+		t.Errorf("Expected different tools to get independent arguments, both got %v", first)
+	}
+}
+
+func TestGenerateSampleArguments_Bounds(t *testing.T) {
+	for seed := range int64(50) {
+		args, err := generateSampleArguments("bounds", map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"port":    map[string]any{"type": "integer", "minimum": float64(1024), "maximum": float64(1030)},
+				"even":    map[string]any{"type": "integer", "exclusiveMinimum": float64(0), "maximum": float64(10), "multipleOf": float64(2)},
+				"below":   map[string]any{"type": "integer", "maximum": float64(-5)},
+				"ratio":   map[string]any{"type": "number", "exclusiveMinimum": float64(0), "multipleOf": 0.25},
+				"code":    map[string]any{"type": "string", "minLength": float64(12), "maxLength": float64(12)},
+				"initial": map[string]any{"type": []any{"null", "string"}, "maxLength": float64(1)},
+			},
+			"required": []any{"port", "even", "below", "ratio", "code", "initial"},
+		}, seed)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		obj := args.(map[string]any)
+
+		if port := obj["port"].(int64); port < 1024 || port > 1030 {
+			t.Errorf("seed %d: port %d out of range", seed, port)
+		}
+		if even := obj["even"].(int64); even <= 0 || even > 10 || even%2 != 0 {
+			t.Errorf("seed %d: even %d violates bounds or multipleOf", seed, even)
+		}
+		if below := obj["below"].(int64); below > -5 {
+			t.Errorf("seed %d: below %d exceeds maximum", seed, below)
+		}
+		if ratio := obj["ratio"].(float64); ratio != 0.25 {
+			t.Errorf("seed %d: expected smallest valid ratio 0.25, got %v", seed, ratio)
+		}
+		if code := obj["code"].(string); len(code) != 12 {
+			t.Errorf("seed %d: code %q should be exactly 12 characters", seed, code)
+		}
+		if initial := obj["initial"].(string); len(initial) != 1 {
+			t.Errorf("seed %d: initial %q should be 1 character", seed, initial)
+		}
+	}
+}
+
+func TestGenerateSampleArguments_MultipleOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema map[string]any
+		valid  func(v float64) bool
+	}{
+		{
+			name:   "integer_fractional_step",
+			schema: map[string]any{"type": "integer", "minimum": float64(1), "maximum": float64(20), "multipleOf": 2.5},
+			valid:  func(v float64) bool { return v >= 1 && v <= 20 && math.Mod(v, 5) == 0 },
+		},
+		{
+			name:   "integer_step_larger_than_range_start",
+			schema: map[string]any{"type": "integer", "minimum": float64(7), "maximum": float64(9), "multipleOf": float64(4)},
+			valid:  func(v float64) bool { return v == 8 },
+		},
+		{
+			name:   "integer_negative_range",
+			schema: map[string]any{"type": "integer", "minimum": float64(-9), "maximum": float64(-1), "multipleOf": float64(3)},
+			valid:  func(v float64) bool { return v >= -9 && v <= -1 && math.Mod(v, 3) == 0 },
+		},
+		{
+			name:   "number_honours_maximum",
+			schema: map[string]any{"type": "number", "minimum": float64(0.05), "maximum": 0.1, "multipleOf": 0.1},
+			valid:  func(v float64) bool { return v == 0.1 },
+		},
+		{
+			name:   "number_honours_exclusive_maximum",
+			schema: map[string]any{"type": "number", "minimum": float64(1), "exclusiveMaximum": float64(3), "multipleOf": 1.5},
+			valid:  func(v float64) bool { return v == 1.5 },
+		},
+		{
+			name:   "number_fractional_step_without_noise",
+			schema: map[string]any{"type": "number", "minimum": 0.25, "maximum": float64(1), "multipleOf": 0.1},
+			valid:  func(v float64) bool { return v == 0.3 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := range int64(50) {
+				args, err := generateSampleArguments("multiple", map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"value": tt.schema},
+					"required":   []any{"value"},
+				}, seed)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				var value float64
+				switch v := args.(map[string]any)["value"].(type) {
+				case int64:
+					value = float64(v)
+				case float64:
+					value = v
+				default:
+					t.Fatalf("Unexpected value type %T", v)
+				}
+				if !tt.valid(value) {
+					t.Fatalf("seed %d: value %v does not satisfy %v", seed, value, tt.schema)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spandigital/mcp-server-dump/internal/transport"
+)
+
+// serverStderrTailLines is how many lines of server stderr are included in connection errors
+const serverStderrTailLines = 20
+
+// serverStderr collects a command server's standard error so startup failures can be diagnosed.
+// The most recent output is always kept in memory; --verbose also streams it to stderr with a
+// prefix and --server-log saves all of it to a file. A nil *serverStderr is valid and does nothing.
+type serverStderr struct {
+	buffer  *transport.StderrBuffer
+	verbose *transport.PrefixWriter
+	logFile *os.File
+}
+
+// newServerStderr sets up stderr capture according to the connection options
+func newServerStderr(conn *ConnectionOptions) (*serverStderr, error) {
+	s := &serverStderr{buffer: transport.NewStderrBuffer(transport.DefaultStderrBufferSize)}
+
+	if conn.Verbose {
+		s.verbose = transport.NewPrefixWriter(os.Stderr, "[server] ")
+	}
+
+	if conn.ServerLog != "" {
+		file, err := os.OpenFile(filepath.Clean(conn.ServerLog), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600) // #nosec G304 - log path is provided by user intentionally
+		if err != nil {
+			return nil, fmt.Errorf("failed to open server log: %w", err)
+		}
+		s.logFile = file
+	}
+
+	return s, nil
+}
+
+// writer returns the writer to attach to the server process's stderr
+func (s *serverStderr) writer() io.Writer {
+	writers := []io.Writer{s.buffer}
+	if s.verbose != nil {
+		writers = append(writers, s.verbose)
+	}
+	if s.logFile != nil {
+		writers = append(writers, s.logFile)
+	}
+	return io.MultiWriter(writers...)
+}
+
+// annotate appends the tail of the server's stderr to an error, if the server wrote anything
+func (s *serverStderr) annotate(err error) error {
+	if s == nil {
+		return err
+	}
+	tail := s.buffer.Tail(serverStderrTailLines)
+	if strings.TrimSpace(tail) == "" {
+		return err
+	}
+	return fmt.Errorf("%w\n\nServer stderr (last %d lines):\n  %s", err, serverStderrTailLines, strings.ReplaceAll(tail, "\n", "\n  "))
+}
+
+// close flushes the verbose stream and closes the server log. Call it after the session has
+// been closed, since closing the session waits for the server to finish writing.
+func (s *serverStderr) close() {
+	if s == nil {
+		return
+	}
+	if s.verbose != nil {
+		if err := s.verbose.Flush(); err != nil {
+			log.Printf("Warning: failed to flush server output: %v", err)
+		}
+	}
+	if s.logFile != nil {
+		if err := s.logFile.Close(); err != nil {
+			log.Printf("Warning: failed to close server log: %v", err)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCreateMCPSession_ServerStderr(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("requires /bin/sh")
+	}
+
+	logPath := filepath.Join(t.TempDir(), "server.log")
+	conn := &ConnectionOptions{
+		Transport: "command",
+		ServerLog: logPath,
+	}
+	args := []string{"/bin/sh", "-c", "echo 'starting server' >&2; echo 'error: API_KEY is not set' >&2; exit 1"}
+
+	_, _, _, err := createMCPSession(context.Background(), conn, args)
+	if err == nil {
+		t.Fatal("Expected connection to fail")
+	}
+	for _, want := range []string{"failed to connect to MCP server", "Server stderr", "error: API_KEY is not set"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to contain %q, got: %v", want, err)
+		}
+	}
+
+	logData, readErr := os.ReadFile(logPath)
+	if readErr != nil {
+		t.Fatalf("Failed to read server log: %v", readErr)
+	}
+	if string(logData) != "starting server\nerror: API_KEY is not set\n" {
+		t.Errorf("Unexpected server log contents: %q", logData)
+	}
+}
+
+func TestServerStderr_AnnotateWithoutOutput(t *testing.T) {
+	s, err := newServerStderr(&ConnectionOptions{})
+	if err != nil {
+		t.Fatalf("newServerStderr failed: %v", err)
+	}
+	base := os.ErrNotExist
+	if got := s.annotate(base); got != base {
+		t.Errorf("Expected error unchanged when the server wrote nothing, got %v", got)
+	}
+
+	var nilStderr *serverStderr
+	if got := nilStderr.annotate(base); got != base {
+		t.Errorf("Expected nil capture to leave the error unchanged, got %v", got)
+	}
+	nilStderr.close()
+}
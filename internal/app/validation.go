@@ -0,0 +1,115 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"github.com/spandigital/mcp-server-dump/internal/model"
+)
+
+// validateToolCall checks a call's arguments against the tool's input schema and, for successful
+// calls, its structured result against the output schema. Schemas that cannot be compiled are
+// logged and skipped, since they say nothing about whether the call itself was valid.
+func validateToolCall(tool *model.Tool, result *model.ToolCall) {
+	if tool == nil {
+		return
+	}
+
+	if tool.InputSchema != nil {
+		args := result.Arguments
+		if args == nil {
+			// Omitted arguments are equivalent to an empty object
+			args = map[string]any{}
+		}
+		if err := validateAgainstSchema(tool.InputSchema, args); err != nil {
+			handleValidationError(tool.Name, "input", err, &result.InputValidationErrors)
+		}
+	}
+
+	if tool.OutputSchema == nil || result.Failed() {
+		return
+	}
+	if result.StructuredContent == nil {
+		result.OutputValidationErrors = append(result.OutputValidationErrors, "tool declares an output schema but returned no structured content")
+		return
+	}
+	if err := validateAgainstSchema(tool.OutputSchema, result.StructuredContent); err != nil {
+		handleValidationError(tool.Name, "output", err, &result.OutputValidationErrors)
+	}
+}
+
+// errSchemaUnusable marks schemas that could not be compiled for validation
+var errSchemaUnusable = errors.New("schema cannot be used for validation")
+
+// handleValidationError records a validation failure, or logs a warning if the schema was unusable
+func handleValidationError(toolName, kind string, err error, into *[]string) {
+	if errors.Is(err, errSchemaUnusable) {
+		log.Printf("Warning: Skipping %s validation for %s: %v", kind, toolName, err)
+		return
+	}
+	*into = append(*into, err.Error())
+	log.Printf("Warning: Tool call %s failed %s validation: %v", toolName, kind, err)
+}
+
+// validateAgainstSchema validates a value against a JSON Schema given in any Go representation.
+// Both schema and value are round-tripped through JSON so the validator sees plain JSON values.
+func validateAgainstSchema(schema, value any) error {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errSchemaUnusable, err)
+	}
+	var compiled jsonschema.Schema
+	if err := json.Unmarshal(data, &compiled); err != nil {
+		return fmt.Errorf("%w: %w", errSchemaUnusable, err)
+	}
+	resolved, err := compiled.Resolve(nil)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errSchemaUnusable, err)
+	}
+
+	instanceData, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode value: %w", err)
+	}
+	var instance any
+	if err := json.Unmarshal(instanceData, &instance); err != nil {
+		return fmt.Errorf("failed to decode value: %w", err)
+	}
+
+	if err := resolved.Validate(instance); err != nil {
+		// Unsupported drafts are reported by Validate rather than Resolve
+		if strings.HasPrefix(err.Error(), "cannot validate version") {
+			return fmt.Errorf("%w: %w", errSchemaUnusable, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// strictCallFailures lists the tool calls that fail --strict-calls: results that violate the
+// output schema, outcomes that differ from the calls file, and invalid arguments unless the
+// call was expected to fail.
+func strictCallFailures(calls []model.ToolCall) []string {
+	var failures []string
+	for _, call := range calls {
+		name := call.ToolName
+		if call.Label != "" {
+			name += " (" + call.Label + ")"
+		}
+
+		switch {
+		case len(call.OutputValidationErrors) > 0:
+			failures = append(failures, name+": structured result does not match output schema")
+		case call.OutcomeMismatch:
+			failures = append(failures, name+": expected outcome "+call.ExpectedOutcome+" not met")
+		case len(call.InputValidationErrors) > 0 && call.ExpectedOutcome != model.OutcomeError:
+			failures = append(failures, name+": arguments do not match input schema")
+		}
+	}
+	return failures
+}
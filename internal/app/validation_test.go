@@ -0,0 +1,97 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/spandigital/mcp-server-dump/internal/model"
+)
+
+func TestValidateToolCall(t *testing.T) {
+	tool := &model.Tool{
+		Name: "get_weather",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"location": map[string]any{"type": "string"}},
+			"required":   []any{"location"},
+		},
+		OutputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"temperature": map[string]any{"type": "number"}},
+			"required":   []any{"temperature"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		call       model.ToolCall
+		wantInput  bool
+		wantOutput bool
+	}{
+		{
+			name: "valid_call",
+			call: model.ToolCall{Arguments: map[string]any{"location": "London"}, StructuredContent: map[string]any{"temperature": 12.5}},
+		},
+		{
+			name:      "missing_required_argument",
+			call:      model.ToolCall{Arguments: nil, StructuredContent: map[string]any{"temperature": 12.5}},
+			wantInput: true,
+		},
+		{
+			name:       "result_violates_output_schema",
+			call:       model.ToolCall{Arguments: map[string]any{"location": "London"}, StructuredContent: map[string]any{"temperature": "warm"}},
+			wantOutput: true,
+		},
+		{
+			name:       "missing_structured_content",
+			call:       model.ToolCall{Arguments: map[string]any{"location": "London"}},
+			wantOutput: true,
+		},
+		{
+			name: "failed_call_skips_output_validation",
+			call: model.ToolCall{Arguments: map[string]any{"location": "London"}, IsError: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call := tt.call
+			validateToolCall(tool, &call)
+			if got := len(call.InputValidationErrors) > 0; got != tt.wantInput {
+				t.Errorf("Expected input errors=%v, got %v", tt.wantInput, call.InputValidationErrors)
+			}
+			if got := len(call.OutputValidationErrors) > 0; got != tt.wantOutput {
+				t.Errorf("Expected output errors=%v, got %v", tt.wantOutput, call.OutputValidationErrors)
+			}
+		})
+	}
+}
+
+func TestValidateToolCall_UnusableSchemaIsSkipped(t *testing.T) {
+	tool := &model.Tool{
+		Name:        "legacy",
+		InputSchema: map[string]any{"$schema": "http://json-schema.org/draft-04/schema#", "type": "object", "required": []any{"x"}},
+	}
+	call := model.ToolCall{}
+	validateToolCall(tool, &call)
+	if len(call.InputValidationErrors) != 0 {
+		t.Errorf("Expected unsupported draft to be skipped, got %v", call.InputValidationErrors)
+	}
+}
+
+func TestStrictCallFailures(t *testing.T) {
+	calls := []model.ToolCall{
+		{ToolName: "ok"},
+		{ToolName: "bad_output", OutputValidationErrors: []string{"x"}},
+		{ToolName: "mismatch", Label: "expects error", ExpectedOutcome: model.OutcomeError, OutcomeMismatch: true},
+		{ToolName: "bad_input", InputValidationErrors: []string{"x"}},
+		{ToolName: "bad_input_expected", InputValidationErrors: []string{"x"}, ExpectedOutcome: model.OutcomeError},
+	}
+
+	failures := strictCallFailures(calls)
+	if len(failures) != 3 {
+		t.Fatalf("Expected 3 failures, got %d: %v", len(failures), failures)
+	}
+	if failures[1] != "mismatch (expects error): expected outcome error not met" {
+		t.Errorf("Unexpected failure message: %q", failures[1])
+	}
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// AuthorizeWithClientCredentials performs the OAuth 2.0 client credentials grant (RFC 6749 Section 4.4).
+// It needs no user interaction, which makes it the flow for CI pipelines and other machine-to-machine use.
+// The client authenticates with its secret using client_secret_basic or client_secret_post, or with
+// a private_key_jwt assertion when a ClientKey is configured. The MCP server is sent as the
+// RFC 8707 resource parameter so the token is bound to it.
+func AuthorizeWithClientCredentials(ctx context.Context, cfg *Config) (*oauth2.Token, error) {
+	if cfg.TokenURL == "" {
+		return nil, fmt.Errorf("token endpoint must be configured")
+	}
+	if cfg.ResourceURI == "" {
+		return nil, fmt.Errorf("resource URI (MCP server endpoint) must be specified")
+	}
+	if cfg.ClientID == "" || (cfg.ClientSecret == "" && cfg.ClientKey == nil) {
+		return nil, fmt.Errorf("client credentials flow requires a client ID and a client secret or private key")
+	}
+
+	token, err := clientCredentialsConfig(cfg).Token(clientCredentialsContext(ctx, cfg))
+	if err != nil {
+		return nil, fmt.Errorf("client credentials token request failed: %w", err)
+	}
+
+	// Logged to stderr: in non-interactive runs stdout carries the documentation output
+	log.Printf("✓ Obtained access token with client credentials")
+	return token, nil
+}
+
+// clientCredentialsConfig builds the golang.org/x/oauth2 client credentials configuration
+func clientCredentialsConfig(cfg *Config) *clientcredentials.Config {
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = DefaultScopes()
+	}
+
+	return &clientcredentials.Config{
+		ClientID:       cfg.ClientID,
+		ClientSecret:   cfg.ClientSecret,
+		TokenURL:       cfg.TokenURL,
+		Scopes:         scopes,
+		EndpointParams: url.Values{"resource": {cfg.ResourceURI}},
+		AuthStyle:      cfg.authStyle(),
+	}
+}
+
+// clientCredentialsContext returns a context whose token requests use the transport stored in ctx
+// and authenticate the client as cfg requires
+func clientCredentialsContext(ctx context.Context, cfg *Config) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, tokenClient(ctx, cfg))
+}
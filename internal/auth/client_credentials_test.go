@@ -0,0 +1,271 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/spandigital/mcp-server-dump/internal/configdir"
+)
+
+// testAuthServer is an httptest authorization server that issues numbered client credentials tokens
+type testAuthServer struct {
+	*httptest.Server
+
+	clientID     string
+	clientSecret string
+	expiresIn    int
+
+	mu       sync.Mutex
+	requests []*http.Request
+	forms    []map[string]string
+}
+
+func newTestAuthServer(t *testing.T, expiresIn int) *testAuthServer {
+	t.Helper()
+	as := &testAuthServer{clientID: "ci-client", clientSecret: "s3cret", expiresIn: expiresIn}
+	as.Server = httptest.NewServer(http.HandlerFunc(as.handleToken))
+	t.Cleanup(as.Close)
+	return as
+}
+
+func (as *testAuthServer) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	form := map[string]string{}
+	for key := range r.PostForm {
+		form[key] = r.PostForm.Get(key)
+	}
+
+	as.mu.Lock()
+	as.requests = append(as.requests, r)
+	as.forms = append(as.forms, form)
+	count := len(as.requests)
+	as.mu.Unlock()
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = form["client_id"], form["client_secret"]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if form["grant_type"] != "client_credentials" || clientID != as.clientID || clientSecret != as.clientSecret {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"access_token": fmt.Sprintf("token-%d", count),
+		"token_type":   "Bearer",
+		"expires_in":   as.expiresIn,
+	})
+}
+
+func (as *testAuthServer) requestCount() int {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	return len(as.requests)
+}
+
+func (as *testAuthServer) config(method string) *Config {
+	return &Config{
+		ClientID:                as.clientID,
+		ClientSecret:            as.clientSecret,
+		Scopes:                  []string{"mcp:tools", "mcp:resources"},
+		ResourceURI:             "https://mcp.example.com/mcp",
+		TokenURL:                as.URL + "/token",
+		FlowType:                FlowTypeClientCredentials,
+		TokenEndpointAuthMethod: method,
+	}
+}
+
+func TestAuthorizeWithClientCredentials(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		wantBasic bool
+	}{
+		{"client_secret_basic", AuthMethodClientSecretBasic, true},
+		{"client_secret_post", AuthMethodClientSecretPost, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			as := newTestAuthServer(t, 3600)
+
+			token, err := Authorize(context.Background(), as.config(tt.method))
+			if err != nil {
+				t.Fatalf("Authorize failed: %v", err)
+			}
+			if token.AccessToken != "token-1" {
+				t.Errorf("Expected token-1, got %q", token.AccessToken)
+			}
+
+			req, form := as.requests[0], as.forms[0]
+			if _, _, ok := req.BasicAuth(); ok != tt.wantBasic {
+				t.Errorf("Expected HTTP Basic credentials=%v", tt.wantBasic)
+			}
+			if _, ok := form["client_secret"]; ok == tt.wantBasic {
+				t.Errorf("Expected client_secret in the form=%v, got form %v", !tt.wantBasic, form)
+			}
+			if form["resource"] != "https://mcp.example.com/mcp" {
+				t.Errorf("Expected RFC 8707 resource parameter, got %q", form["resource"])
+			}
+			if form["scope"] != "mcp:tools mcp:resources" {
+				t.Errorf("Expected requested scopes, got %q", form["scope"])
+			}
+		})
+	}
+}
+
+func TestAuthorizeWithClientCredentials_Errors(t *testing.T) {
+	as := newTestAuthServer(t, 3600)
+
+	noSecret := as.config(AuthMethodClientSecretBasic)
+	noSecret.ClientSecret = ""
+	if _, err := Authorize(context.Background(), noSecret); err == nil || !strings.Contains(err.Error(), "client secret") {
+		t.Errorf("Expected missing secret error, got %v", err)
+	}
+
+	wrongSecret := as.config(AuthMethodClientSecretPost)
+	wrongSecret.ClientSecret = "wrong"
+	if _, err := Authorize(context.Background(), wrongSecret); err == nil || !strings.Contains(err.Error(), "invalid_client") {
+		t.Errorf("Expected invalid_client error, got %v", err)
+	}
+}
+
+func TestOAuthRoundTripper_ClientCredentials(t *testing.T) {
+	t.Setenv(configdir.HomeEnv, t.TempDir())
+
+	var mu sync.Mutex
+	var seen []string
+	resource := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Header.Get("Authorization"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer resource.Close()
+
+	get := func(t *testing.T, rt http.RoundTripper) {
+		t.Helper()
+		resp, err := (&http.Client{Transport: rt}).Get(resource.URL)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	t.Run("new token when expired", func(t *testing.T) {
+		// Tokens this short-lived are already inside the refresh window, so each request needs a new one
+		as := newTestAuthServer(t, 1)
+		rt, err := NewOAuthRoundTripper(nil, as.config(""))
+		if err != nil {
+			t.Fatalf("NewOAuthRoundTripper failed: %v", err)
+		}
+		seen = nil
+
+		get(t, rt)
+		get(t, rt)
+
+		if as.requestCount() != 2 {
+			t.Errorf("Expected a token request per expired token, got %d", as.requestCount())
+		}
+		if len(seen) != 2 || seen[0] != "Bearer token-1" || seen[1] != "Bearer token-2" {
+			t.Errorf("Expected each request to carry a fresh token, got %v", seen)
+		}
+	})
+
+	t.Run("cached token reused", func(t *testing.T) {
+		as := newTestAuthServer(t, 3600)
+		config := as.config(AuthMethodClientSecretBasic)
+		config.UseCache = true
+
+		first, err := NewOAuthRoundTripper(nil, config)
+		if err != nil {
+			t.Fatalf("NewOAuthRoundTripper failed: %v", err)
+		}
+		get(t, first)
+
+		cached, err := LoadToken(config.ResourceURI)
+		if err != nil || cached == nil || cached.AccessToken != "token-1" {
+			t.Fatalf("Expected token-1 to be cached, got %+v, %v", cached, err)
+		}
+
+		// A new run picks up the cached token without another token request
+		second, err := NewOAuthRoundTripper(nil, config)
+		if err != nil {
+			t.Fatalf("NewOAuthRoundTripper failed: %v", err)
+		}
+		seen = nil
+		get(t, second)
+
+		if as.requestCount() != 1 {
+			t.Errorf("Expected the cached token to be reused, got %d token requests", as.requestCount())
+		}
+		if len(seen) != 1 || seen[0] != "Bearer token-1" {
+			t.Errorf("Expected the cached token on the request, got %v", seen)
+		}
+	})
+}
+
+func TestOAuthRoundTripper_SharedTokenCache(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(configdir.HomeEnv, home)
+
+	as := newTestAuthServer(t, 3600)
+	config := as.config(AuthMethodClientSecretBasic)
+	config.UseCache = true
+	expired := &oauth2.Token{AccessToken: "expired", TokenType: "Bearer", Expiry: time.Now().Add(-time.Hour)}
+	if err := SaveToken(expired, config.ResourceURI, config.Scopes); err != nil {
+		t.Fatalf("SaveToken failed: %v", err)
+	}
+
+	rt, err := NewOAuthRoundTripper(nil, config)
+	if err != nil {
+		t.Fatalf("NewOAuthRoundTripper failed: %v", err)
+	}
+
+	// Another process sharing the cache replaces the expired token first
+	shared := &oauth2.Token{AccessToken: "other-process", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)}
+	if err := SaveToken(shared, config.ResourceURI, config.Scopes); err != nil {
+		t.Fatalf("SaveToken failed: %v", err)
+	}
+	token, err := rt.getValidToken(context.Background())
+	if err != nil || token.AccessToken != "other-process" {
+		t.Fatalf("Expected the token cached by the other process, got %+v, %v", token, err)
+	}
+	if as.requestCount() != 0 {
+		t.Errorf("Expected no token request, got %d", as.requestCount())
+	}
+
+	// Without a newer cached token, a new one is requested and cached for the others
+	if err := SaveToken(expired, config.ResourceURI, config.Scopes); err != nil {
+		t.Fatalf("SaveToken failed: %v", err)
+	}
+	rt, err = NewOAuthRoundTripper(nil, config)
+	if err != nil {
+		t.Fatalf("NewOAuthRoundTripper failed: %v", err)
+	}
+	if token, err := rt.getValidToken(context.Background()); err != nil || token.AccessToken != "token-1" {
+		t.Fatalf("Expected a new token, got %+v, %v", token, err)
+	}
+	cached, err := LoadToken(config.ResourceURI)
+	if err != nil || cached == nil || cached.AccessToken != "token-1" {
+		t.Errorf("Expected token-1 to be cached, got %+v, %v", cached, err)
+	}
+	if _, err := os.Stat(filepath.Join(home, "tokens", tokenCacheName(config.ResourceURI)+".json")); err != nil {
+		t.Errorf("Expected the token under %s: %v", configdir.HomeEnv, err)
+	}
+}
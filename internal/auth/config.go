@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"net/http"
 	"time"
 
 	"golang.org/x/oauth2"
@@ -23,6 +24,21 @@ const (
 	FlowTypeClientCredentials FlowType = "client-credentials"
 )
 
+// Client authentication methods for the token endpoint, named as in RFC 7591 token_endpoint_auth_method.
+const (
+	// AuthMethodClientSecretBasic sends the client credentials with HTTP Basic authentication
+	AuthMethodClientSecretBasic = "client_secret_basic"
+
+	// AuthMethodClientSecretPost sends the client credentials as form parameters in the request body
+	AuthMethodClientSecretPost = "client_secret_post"
+
+	// AuthMethodPrivateKeyJWT sends a JWT signed with the client's private key (RFC 7523)
+	AuthMethodPrivateKeyJWT = "private_key_jwt"
+
+	// AuthMethodNone is used by public clients, which do not authenticate
+	AuthMethodNone = "none"
+)
+
 // Config holds OAuth 2.1 configuration for authenticating with MCP servers.
 type Config struct {
 	// ClientID is the OAuth client identifier (required unless using DCR)
@@ -62,6 +78,42 @@ type Config struct {
 
 	// UseDCR enables Dynamic Client Registration (RFC 7591)
 	UseDCR bool
+
+	// TokenEndpointAuthMethod is how a confidential client authenticates to the token endpoint
+	// (AuthMethodClientSecretBasic or AuthMethodClientSecretPost); empty tries Basic, then form parameters.
+	// It is ignored when ClientKey is set, which always uses AuthMethodPrivateKeyJWT.
+	TokenEndpointAuthMethod string
+
+	// ClientKey authenticates the client with private_key_jwt instead of a client secret (optional)
+	ClientKey *ClientKey
+
+	// TokenStore caches tokens when UseCache is set; nil uses the file store
+	TokenStore TokenStore
+}
+
+// authStyle maps TokenEndpointAuthMethod to the golang.org/x/oauth2 auth style
+func (c *Config) authStyle() oauth2.AuthStyle {
+	// Client assertions are added to the form parameters by clientAssertionTransport
+	if c.ClientKey != nil {
+		return oauth2.AuthStyleInParams
+	}
+
+	switch c.TokenEndpointAuthMethod {
+	case AuthMethodClientSecretBasic:
+		return oauth2.AuthStyleInHeader
+	case AuthMethodClientSecretPost:
+		return oauth2.AuthStyleInParams
+	default:
+		return oauth2.AuthStyleAutoDetect
+	}
+}
+
+// tokenTransport wraps base so token requests carry a signed client assertion when ClientKey is set
+func (c *Config) tokenTransport(base http.RoundTripper) http.RoundTripper {
+	if c.ClientKey == nil {
+		return base
+	}
+	return &clientAssertionTransport{base: base, clientID: c.ClientID, key: c.ClientKey}
 }
 
 // TokenCache represents a cached OAuth token for a specific MCP server.
@@ -173,6 +225,12 @@ type ClientRegistration struct {
 	// RegistrationAccessToken is the token for managing this registration
 	RegistrationAccessToken string `json:"registration_access_token,omitempty"`
 
+	// TokenEndpointAuthMethod is the client authentication method that was registered (empty means none)
+	TokenEndpointAuthMethod string `json:"token_endpoint_auth_method,omitempty"`
+
+	// KeyThumbprint identifies the public key registered for private_key_jwt (see ClientKey.Thumbprint)
+	KeyThumbprint string `json:"key_thumbprint,omitempty"`
+
 	// RegisteredAt is when this registration was created
 	RegisteredAt time.Time `json:"registered_at"`
 }
@@ -191,6 +249,12 @@ type ClientRegistrationRequest struct {
 	// TokenEndpointAuthMethod is how the client authenticates to the token endpoint
 	TokenEndpointAuthMethod string `json:"token_endpoint_auth_method"`
 
+	// TokenEndpointAuthSigningAlg is the algorithm used to sign private_key_jwt assertions
+	TokenEndpointAuthSigningAlg string `json:"token_endpoint_auth_signing_alg,omitempty"`
+
+	// JWKS holds the public keys that verify private_key_jwt assertions
+	JWKS *JSONWebKeySet `json:"jwks,omitempty"`
+
 	// Scope is the space-separated list of scopes
 	Scope string `json:"scope,omitempty"`
 }
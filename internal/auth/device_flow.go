@@ -90,7 +90,7 @@ func requestDeviceCode(ctx context.Context, cfg *Config, deviceAuthURL string) (
 		data.Set("resource", cfg.ResourceURI)
 	}
 
-	// Add client secret if provided (for confidential clients)
+	// Add client secret if provided (for confidential clients; private_key_jwt is added by tokenClient)
 	if cfg.ClientSecret != "" {
 		data.Set("client_secret", cfg.ClientSecret)
 	}
@@ -103,7 +103,7 @@ func requestDeviceCode(ctx context.Context, cfg *Config, deviceAuthURL string) (
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := http.DefaultClient.Do(req) //nolint:gosec // G704: URL comes from user-configured OAuth device authorization endpoint
+	resp, err := tokenClient(ctx, cfg).Do(req) //nolint:gosec // G704: URL comes from user-configured OAuth device authorization endpoint
 	if err != nil {
 		return nil, err
 	}
@@ -214,7 +214,7 @@ func attemptTokenExchange(ctx context.Context, cfg *Config, deviceCode string) (
 		data.Set("resource", cfg.ResourceURI)
 	}
 
-	// Add client secret if provided (for confidential clients)
+	// Add client secret if provided (for confidential clients; private_key_jwt is added by tokenClient)
 	if cfg.ClientSecret != "" {
 		data.Set("client_secret", cfg.ClientSecret)
 	}
@@ -227,7 +227,7 @@ func attemptTokenExchange(ctx context.Context, cfg *Config, deviceCode string) (
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := http.DefaultClient.Do(req) //nolint:gosec // G704: URL comes from user-configured OAuth token endpoint
+	resp, err := tokenClient(ctx, cfg).Do(req) //nolint:gosec // G704: URL comes from user-configured OAuth token endpoint
 	if err != nil {
 		return nil, err
 	}
@@ -14,6 +14,11 @@ import (
 // DiscoverFromResponse attempts to discover OAuth endpoints from a 401 Unauthorized response.
 // It parses the WWW-Authenticate header according to RFC 9728 and fetches metadata.
 func DiscoverFromResponse(resp *http.Response) (*Config, error) {
+	ctx := context.Background()
+	if resp.Request != nil {
+		ctx = resp.Request.Context()
+	}
+
 	if resp.StatusCode != http.StatusUnauthorized {
 		return nil, fmt.Errorf("expected 401 Unauthorized response, got %d", resp.StatusCode)
 	}
@@ -36,7 +41,7 @@ func DiscoverFromResponse(resp *http.Response) (*Config, error) {
 	}
 
 	// Fetch protected resource metadata
-	prMetadata, err := fetchProtectedResourceMetadata(metadataURL)
+	prMetadata, err := fetchProtectedResourceMetadata(ctx, metadataURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch protected resource metadata: %w", err)
 	}
@@ -50,7 +55,7 @@ func DiscoverFromResponse(resp *http.Response) (*Config, error) {
 
 	// Try to fetch authorization server metadata (.well-known)
 	// This may fail for servers like GitHub that don't provide RFC 8414 metadata
-	asMetadata, err := fetchAuthServerMetadata(asURL)
+	asMetadata, err := fetchAuthServerMetadata(ctx, asURL)
 	if err != nil {
 		// If .well-known metadata is not available, return partial config with guessed endpoints
 		// Many servers follow standard patterns, so we can make educated guesses
@@ -149,7 +154,7 @@ func extractParam(params, key string) string {
 }
 
 // fetchProtectedResourceMetadata fetches RFC 9728 protected resource metadata.
-func fetchProtectedResourceMetadata(metadataURL string) (*ProtectedResourceMetadata, error) {
+func fetchProtectedResourceMetadata(ctx context.Context, metadataURL string) (*ProtectedResourceMetadata, error) {
 	// If URL doesn't include the well-known path, append it
 	if !strings.Contains(metadataURL, "/.well-known/") {
 		u, err := url.Parse(metadataURL)
@@ -160,7 +165,7 @@ func fetchProtectedResourceMetadata(metadataURL string) (*ProtectedResourceMetad
 		metadataURL = u.String()
 	}
 
-	resp, err := http.Get(metadataURL) //nolint:gosec // G107,G704: URL is constructed from server-provided metadata or user input
+	resp, err := getMetadata(ctx, metadataURL)
 	if err != nil {
 		return nil, err
 	}
@@ -183,8 +188,17 @@ func fetchProtectedResourceMetadata(metadataURL string) (*ProtectedResourceMetad
 	return &metadata, nil
 }
 
+// getMetadata sends a GET request for a metadata document
+func getMetadata(ctx context.Context, metadataURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	return httpClient(ctx).Do(req) //nolint:gosec // G107,G704: URL is constructed from server-provided metadata or user input
+}
+
 // fetchAuthServerMetadata fetches RFC 8414 authorization server metadata.
-func fetchAuthServerMetadata(issuerURL string) (*AuthServerMetadata, error) {
+func fetchAuthServerMetadata(ctx context.Context, issuerURL string) (*AuthServerMetadata, error) {
 	// Build well-known URL according to RFC 8414
 	// Format: <issuer>/.well-known/oauth-authorization-server
 	u, err := url.Parse(issuerURL)
@@ -196,7 +210,7 @@ func fetchAuthServerMetadata(issuerURL string) (*AuthServerMetadata, error) {
 	u.Path = strings.TrimSuffix(u.Path, "/") + "/.well-known/oauth-authorization-server"
 	metadataURL := u.String()
 
-	resp, err := http.Get(metadataURL) //nolint:gosec // G107,G704: URL is constructed from server-provided metadata
+	resp, err := getMetadata(ctx, metadataURL)
 	if err != nil {
 		return nil, err
 	}
@@ -222,7 +236,7 @@ func fetchAuthServerMetadata(issuerURL string) (*AuthServerMetadata, error) {
 // discoverFromWellKnown attempts to discover OAuth endpoints by directly querying
 // the .well-known/oauth-authorization-server endpoint (RFC 8414).
 // This is a fallback when WWW-Authenticate header is not present.
-func discoverFromWellKnown(endpoint string) (*Config, error) {
+func discoverFromWellKnown(ctx context.Context, endpoint string) (*Config, error) {
 	// Parse endpoint URL to get base (scheme + host)
 	endpointURL, err := url.Parse(endpoint)
 	if err != nil {
@@ -233,7 +247,7 @@ func discoverFromWellKnown(endpoint string) (*Config, error) {
 	baseURL := fmt.Sprintf("%s://%s", endpointURL.Scheme, endpointURL.Host)
 
 	// Fetch authorization server metadata directly
-	asMetadata, err := fetchAuthServerMetadata(baseURL)
+	asMetadata, err := fetchAuthServerMetadata(ctx, baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch .well-known metadata: %w", err)
 	}
@@ -344,7 +358,7 @@ func normalizeURLScheme(discoveredURL, referenceURL string) string {
 // discoverFromResponseBody parses a non-standard JSON response body for device flow endpoints
 // and enhances it with .well-known metadata if available.
 // Returns nil if the body doesn't contain device flow information (not an error condition).
-func discoverFromResponseBody(body []byte, endpoint string) *Config {
+func discoverFromResponseBody(ctx context.Context, body []byte, endpoint string) *Config {
 	deviceAuthURL, tokenURL, parseErr := parseDeviceFlowFromBody(body)
 	if parseErr != nil {
 		// Body is not device flow JSON - this is not an error, just means this strategy didn't work
@@ -370,7 +384,7 @@ func discoverFromResponseBody(body []byte, endpoint string) *Config {
 	}
 
 	// Try to get additional metadata from .well-known (optional enhancement)
-	wellKnownConfig, wkErr := discoverFromWellKnown(endpoint)
+	wellKnownConfig, wkErr := discoverFromWellKnown(ctx, endpoint)
 	if wkErr == nil && wellKnownConfig != nil {
 		// Merge .well-known data into config (prefer body-parsed endpoints, add missing fields)
 		if wellKnownConfig.ClientID != "" {
@@ -406,6 +420,7 @@ func DiscoverAndConfigure(ctx context.Context, endpoint string) (*Config, error)
 	req.Header.Set("Accept", "application/json")
 
 	client := &http.Client{
+		Transport: httpTransport(ctx),
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse // Don't follow redirects
 		},
@@ -438,7 +453,7 @@ func DiscoverAndConfigure(ctx context.Context, endpoint string) (*Config, error)
 	// Strategy 2: Parse response body for non-standard device flow advertisement
 	// (Try this BEFORE .well-known to prioritize server-specific device flow info)
 	if readErr == nil && len(body) > 0 {
-		config := discoverFromResponseBody(body, endpoint)
+		config := discoverFromResponseBody(ctx, body, endpoint)
 		if config != nil {
 			return config, nil
 		}
@@ -446,7 +461,7 @@ func DiscoverAndConfigure(ctx context.Context, endpoint string) (*Config, error)
 	}
 
 	// Strategy 3: Try .well-known endpoint directly (fallback)
-	config, wellKnownErr := discoverFromWellKnown(endpoint)
+	config, wellKnownErr := discoverFromWellKnown(ctx, endpoint)
 	if wellKnownErr == nil && config != nil {
 		return config, nil
 	}
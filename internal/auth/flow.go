@@ -20,10 +20,14 @@ import (
 
 // Authorize performs OAuth authorization using the appropriate flow based on configuration.
 // It automatically selects between authorization code flow (with PKCE) and device flow,
-// or uses the explicitly specified flow type.
+// or uses the explicitly specified flow type (including the client credentials grant).
 func Authorize(ctx context.Context, cfg *Config) (*oauth2.Token, error) {
-	// Validate that we have at least one authorization endpoint and token endpoint
-	if cfg.AuthURL == "" && cfg.DeviceAuthURL == "" {
+	// Determine which flow to use
+	flowType := determineFlowType(cfg)
+
+	// Validate that we have at least one authorization endpoint and token endpoint.
+	// The client credentials grant only uses the token endpoint.
+	if cfg.AuthURL == "" && cfg.DeviceAuthURL == "" && flowType != FlowTypeClientCredentials {
 		return nil, fmt.Errorf("authorization endpoint (AuthURL or DeviceAuthURL) must be configured")
 	}
 	if cfg.TokenURL == "" {
@@ -34,16 +38,13 @@ func Authorize(ctx context.Context, cfg *Config) (*oauth2.Token, error) {
 		return nil, fmt.Errorf("resource URI (MCP server endpoint) must be specified")
 	}
 
-	// Determine which flow to use
-	flowType := determineFlowType(cfg)
-
 	switch flowType {
 	case FlowTypeDeviceFlow:
 		return AuthorizeWithDeviceFlow(ctx, cfg)
 	case FlowTypeAuthorizationCode:
 		return authorizeWithAuthCode(ctx, cfg)
 	case FlowTypeClientCredentials:
-		return nil, fmt.Errorf("client credentials flow not yet implemented")
+		return AuthorizeWithClientCredentials(ctx, cfg)
 	case FlowTypeAuto:
 		// This should never happen as determineFlowType always returns a specific flow
 		return nil, fmt.Errorf("flow type auto should have been resolved to a specific flow")
@@ -91,8 +92,9 @@ func authorizeWithAuthCode(ctx context.Context, cfg *Config) (*oauth2.Token, err
 		ClientID:     cfg.ClientID,
 		ClientSecret: cfg.ClientSecret,
 		Endpoint: oauth2.Endpoint{
-			AuthURL:  cfg.AuthURL,
-			TokenURL: cfg.TokenURL,
+			AuthURL:   cfg.AuthURL,
+			TokenURL:  cfg.TokenURL,
+			AuthStyle: cfg.authStyle(),
 		},
 		RedirectURL: "", // Will be set when we know the port
 		Scopes:      scopes,
@@ -154,7 +156,7 @@ func authorizeWithAuthCode(ctx context.Context, cfg *Config) (*oauth2.Token, err
 	// Create context with custom HTTP client that adds resource parameter to token request body
 	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{
 		Transport: &resourceParamTransport{
-			base:     http.DefaultTransport,
+			base:     cfg.tokenTransport(httpTransport(ctx)),
 			resource: cfg.ResourceURI,
 		},
 	})
@@ -310,7 +312,8 @@ func RefreshToken(ctx context.Context, cfg *Config, refreshToken string) (*oauth
 		ClientID:     cfg.ClientID,
 		ClientSecret: cfg.ClientSecret,
 		Endpoint: oauth2.Endpoint{
-			TokenURL: cfg.TokenURL,
+			TokenURL:  cfg.TokenURL,
+			AuthStyle: cfg.authStyle(),
 		},
 	}
 
@@ -322,7 +325,7 @@ func RefreshToken(ctx context.Context, cfg *Config, refreshToken string) (*oauth
 	// Add resource parameter to token refresh request
 	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{
 		Transport: &resourceParamTransport{
-			base:     http.DefaultTransport,
+			base:     cfg.tokenTransport(httpTransport(ctx)),
 			resource: cfg.ResourceURI,
 		},
 	})
@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+)
+
+// httpTransportKey is the context key for the transport used by OAuth requests
+type httpTransportKey struct{}
+
+// WithHTTPTransport returns a context whose OAuth discovery, registration and token requests
+// are sent with transport, so they share the TLS and proxy settings of the MCP connection.
+// This mirrors oauth2.HTTPClient, which serves the same purpose for golang.org/x/oauth2.
+func WithHTTPTransport(ctx context.Context, transport http.RoundTripper) context.Context {
+	return context.WithValue(ctx, httpTransportKey{}, transport)
+}
+
+// httpTransport returns the transport stored in ctx, or http.DefaultTransport
+func httpTransport(ctx context.Context) http.RoundTripper {
+	if transport, ok := ctx.Value(httpTransportKey{}).(http.RoundTripper); ok && transport != nil {
+		return transport
+	}
+	return http.DefaultTransport
+}
+
+// httpClient returns a client for OAuth requests using the transport stored in ctx
+func httpClient(ctx context.Context) *http.Client {
+	return &http.Client{Transport: httpTransport(ctx)}
+}
+
+// tokenClient returns a client for requests that authenticate the client, such as token
+// requests, using the transport stored in ctx and cfg's client authentication
+func tokenClient(ctx context.Context, cfg *Config) *http.Client {
+	return &http.Client{Transport: cfg.tokenTransport(httpTransport(ctx))}
+}
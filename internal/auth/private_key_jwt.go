@@ -0,0 +1,368 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ClientAssertionType is the client_assertion_type for JWT client assertions (RFC 7523 Section 2.2)
+const ClientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// clientAssertionLifetime keeps assertions short-lived; a new one is signed for every token request
+const clientAssertionLifetime = 2 * time.Minute
+
+// signingAlgorithms maps the supported JWS algorithms to their hash functions
+var signingAlgorithms = map[string]crypto.Hash{
+	"RS256": crypto.SHA256, "RS384": crypto.SHA384, "RS512": crypto.SHA512,
+	"PS256": crypto.SHA256, "PS384": crypto.SHA384, "PS512": crypto.SHA512,
+	"ES256": crypto.SHA256, "ES384": crypto.SHA384, "ES512": crypto.SHA512,
+	"EdDSA": 0,
+}
+
+// curveAlgorithms maps each supported ECDSA curve to the only algorithm that uses it
+var curveAlgorithms = map[string]string{"P-256": "ES256", "P-384": "ES384", "P-521": "ES512"}
+
+// ClientKey is the private key a confidential client uses for private_key_jwt authentication
+// (RFC 7523), in place of a client secret.
+type ClientKey struct {
+	// Signer is the RSA, ECDSA or Ed25519 private key
+	Signer crypto.Signer
+
+	// KeyID is sent as the kid header so the server can pick the matching registered key (optional)
+	KeyID string
+
+	// Algorithm is the JWS signing algorithm, e.g. RS256, PS256, ES256 or EdDSA
+	Algorithm string
+}
+
+// LoadClientKey reads a PEM private key (PKCS #8, PKCS #1 or SEC 1) for private_key_jwt.
+// An empty algorithm selects the default for the key type: RS256 for RSA, ES256/ES384/ES512
+// for the P-256/P-384/P-521 curves and EdDSA for Ed25519.
+func LoadClientKey(path, keyID, algorithm string) (*ClientKey, error) {
+	// #nosec G304 - key file path is provided by user intentionally
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	signer, err := parsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key %s: %w", path, err)
+	}
+
+	if algorithm == "" {
+		algorithm = defaultAlgorithm(signer)
+	}
+	if err := checkAlgorithm(signer, algorithm); err != nil {
+		return nil, err
+	}
+
+	return &ClientKey{Signer: signer, KeyID: keyID, Algorithm: algorithm}, nil
+}
+
+// parsePrivateKey decodes the first PEM private key block in data
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM private key found")
+		}
+
+		var key any
+		var err error
+		switch block.Type {
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		case "ENCRYPTED PRIVATE KEY":
+			return nil, fmt.Errorf("encrypted private keys are not supported")
+		default:
+			// Skip certificates and other blocks bundled with the key
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+	}
+}
+
+// defaultAlgorithm returns the usual JWS algorithm for the key type
+func defaultAlgorithm(signer crypto.Signer) string {
+	switch pub := signer.Public().(type) {
+	case *ecdsa.PublicKey:
+		return curveAlgorithms[pub.Curve.Params().Name]
+	case ed25519.PublicKey:
+		return "EdDSA"
+	default:
+		return "RS256"
+	}
+}
+
+// checkAlgorithm reports an error if algorithm is unknown or cannot be used with the key
+func checkAlgorithm(signer crypto.Signer, algorithm string) error {
+	if _, ok := signingAlgorithms[algorithm]; !ok {
+		return fmt.Errorf("unsupported signing algorithm %q (use RS256, RS384, RS512, PS256, PS384, PS512, ES256, ES384, ES512 or EdDSA)", algorithm)
+	}
+
+	var compatible bool
+	switch pub := signer.Public().(type) {
+	case *rsa.PublicKey:
+		compatible = strings.HasPrefix(algorithm, "RS") || strings.HasPrefix(algorithm, "PS")
+	case *ecdsa.PublicKey:
+		compatible = algorithm == curveAlgorithms[pub.Curve.Params().Name]
+	case ed25519.PublicKey:
+		compatible = algorithm == "EdDSA"
+	default:
+		return fmt.Errorf("unsupported private key type %T", pub)
+	}
+	if !compatible {
+		return fmt.Errorf("signing algorithm %s cannot be used with a %s key", algorithm, keyType(signer))
+	}
+	return nil
+}
+
+// keyType describes the key for error messages
+func keyType(signer crypto.Signer) string {
+	switch pub := signer.Public().(type) {
+	case *rsa.PublicKey:
+		return "RSA"
+	case *ecdsa.PublicKey:
+		return "ECDSA " + pub.Curve.Params().Name
+	case ed25519.PublicKey:
+		return "Ed25519"
+	default:
+		return fmt.Sprintf("%T", pub)
+	}
+}
+
+// Assertion signs a client assertion JWT (RFC 7523 Section 3) identifying clientID to audience,
+// which is the endpoint the assertion is sent to
+func (k *ClientKey) Assertion(clientID, audience string) (string, error) {
+	header := map[string]string{"alg": k.Algorithm, "typ": "JWT"}
+	if k.KeyID != "" {
+		header["kid"] = k.KeyID
+	}
+
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", fmt.Errorf("failed to generate assertion ID: %w", err)
+	}
+	now := time.Now()
+	claims := map[string]any{
+		"iss": clientID,
+		"sub": clientID,
+		"aud": audience,
+		"jti": base64.RawURLEncoding.EncodeToString(jti),
+		"iat": now.Unix(),
+		"exp": now.Add(clientAssertionLifetime).Unix(),
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	signature, err := k.sign([]byte(signingInput))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign client assertion: %w", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// sign produces the JWS signature of message for the key's algorithm
+func (k *ClientKey) sign(message []byte) ([]byte, error) {
+	hash, ok := signingAlgorithms[k.Algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", k.Algorithm)
+	}
+
+	// Ed25519 signs the message itself
+	if hash == 0 {
+		return k.Signer.Sign(rand.Reader, message, crypto.Hash(0))
+	}
+
+	h := hash.New()
+	h.Write(message)
+	digest := h.Sum(nil)
+
+	var opts crypto.SignerOpts = hash
+	if strings.HasPrefix(k.Algorithm, "PS") {
+		opts = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: hash}
+	}
+	signature, err := k.Signer.Sign(rand.Reader, digest, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// JWS uses the fixed-size R || S form instead of the ASN.1 encoding crypto.Signer returns
+	if pub, ok := k.Signer.Public().(*ecdsa.PublicKey); ok {
+		return ecdsaRawSignature(signature, pub.Curve)
+	}
+	return signature, nil
+}
+
+// ecdsaRawSignature converts an ASN.1 ECDSA signature to the R || S form of RFC 7518 Section 3.4
+func ecdsaRawSignature(der []byte, curve elliptic.Curve) ([]byte, error) {
+	var sig struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, fmt.Errorf("invalid ECDSA signature: %w", err)
+	}
+	size := (curve.Params().BitSize + 7) / 8
+	raw := make([]byte, 2*size)
+	sig.R.FillBytes(raw[:size])
+	sig.S.FillBytes(raw[size:])
+	return raw, nil
+}
+
+// JSONWebKey is the public part of a client key in JWK form (RFC 7517)
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet is a set of JSON Web Keys, as sent in the jwks registration parameter
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// PublicJWK returns the public key as a JWK for Dynamic Client Registration
+func (k *ClientKey) PublicJWK() (JSONWebKey, error) {
+	jwk := JSONWebKey{Use: "sig", KeyID: k.KeyID, Algorithm: k.Algorithm}
+	encode := base64.RawURLEncoding.EncodeToString
+
+	switch pub := k.Signer.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encode(pub.N.Bytes())
+		jwk.E = encode(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		ecdhKey, err := pub.ECDH()
+		if err != nil {
+			return JSONWebKey{}, err
+		}
+		// The uncompressed point is 0x04 || X || Y
+		point := ecdhKey.Bytes()[1:]
+		jwk.KeyType = "EC"
+		jwk.Curve = pub.Curve.Params().Name
+		jwk.X = encode(point[:len(point)/2])
+		jwk.Y = encode(point[len(point)/2:])
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encode(pub)
+	default:
+		return JSONWebKey{}, fmt.Errorf("unsupported public key type %T", pub)
+	}
+	return jwk, nil
+}
+
+// Thumbprint identifies the registered form of the key: the base64url SHA-256 hash of its
+// public JWK. Unlike an RFC 7638 thumbprint it covers kid and alg, since the authorization
+// server matches assertions against those as well.
+func (k *ClientKey) Thumbprint() (string, error) {
+	jwk, err := k.PublicJWK()
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(jwk)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// clientAssertionTransport authenticates form POSTs to the token and device authorization
+// endpoints with a freshly signed client assertion, replacing any client secret
+type clientAssertionTransport struct {
+	base     http.RoundTripper
+	clientID string
+	key      *ClientKey
+}
+
+// RoundTrip adds client_assertion_type and client_assertion to form-encoded POST requests
+func (t *clientAssertionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPost || !strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		return t.base.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token request: %w", err)
+	}
+
+	// The audience is the endpoint itself, without query or fragment
+	audience := *req.URL
+	audience.RawQuery, audience.Fragment = "", ""
+	assertion, err := t.key.Assertion(t.clientID, audience.String())
+	if err != nil {
+		return nil, err
+	}
+
+	values.Del("client_secret")
+	values.Set("client_id", t.clientID)
+	values.Set("client_assertion_type", ClientAssertionType)
+	values.Set("client_assertion", assertion)
+	encoded := []byte(values.Encode())
+
+	// Clone request to avoid modifying original (RoundTripper contract requirement)
+	clonedReq := req.Clone(req.Context())
+	clonedReq.Header.Del("Authorization")
+	clonedReq.Body = io.NopCloser(bytes.NewReader(encoded))
+	clonedReq.ContentLength = int64(len(encoded))
+	clonedReq.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(encoded)), nil
+	}
+	return t.base.RoundTrip(clonedReq)
+}
@@ -0,0 +1,371 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spandigital/mcp-server-dump/internal/configdir"
+)
+
+// writeKeyFile writes key to a PEM file using the given block type and encoding
+func writeKeyFile(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "client.key")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+	return path
+}
+
+// pkcs8KeyFile writes key as a PKCS #8 PEM file
+func pkcs8KeyFile(t *testing.T, key crypto.Signer) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	return writeKeyFile(t, "PRIVATE KEY", der)
+}
+
+// verifyAssertion checks the signature of a client assertion and returns its header and claims
+func verifyAssertion(t *testing.T, assertion string, pub crypto.PublicKey) (header, claims map[string]any) {
+	t.Helper()
+	parts := strings.Split(assertion, ".")
+	if len(parts) != 3 {
+		t.Fatalf("Expected a compact JWS, got %q", assertion)
+	}
+	decode := func(part string, v any) {
+		data, err := base64.RawURLEncoding.DecodeString(part)
+		if err != nil {
+			t.Fatalf("Invalid base64url segment: %v", err)
+		}
+		if v != nil {
+			if err := json.Unmarshal(data, v); err != nil {
+				t.Fatalf("Invalid JSON segment: %v", err)
+			}
+		}
+	}
+	decode(parts[0], &header)
+	decode(parts[1], &claims)
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+
+	message := []byte(parts[0] + "." + parts[1])
+	alg, _ := header["alg"].(string)
+	hash := signingAlgorithms[alg]
+	var digest []byte
+	if hash != 0 {
+		h := hash.New()
+		h.Write(message)
+		digest = h.Sum(nil)
+	}
+
+	var valid bool
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		if strings.HasPrefix(alg, "PS") {
+			valid = rsa.VerifyPSS(key, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+		} else {
+			valid = rsa.VerifyPKCS1v15(key, hash, digest, signature) == nil
+		}
+	case *ecdsa.PublicKey:
+		size := len(signature) / 2
+		r, s := new(big.Int).SetBytes(signature[:size]), new(big.Int).SetBytes(signature[size:])
+		valid = ecdsa.Verify(key, digest, r, s)
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, message, signature)
+	}
+	if !valid {
+		t.Fatalf("Client assertion signature (%s) does not verify", alg)
+	}
+	return header, claims
+}
+
+func TestLoadClientKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	p256Key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384Key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	sec1, _ := x509.MarshalECPrivateKey(p384Key)
+
+	rsaFile := pkcs8KeyFile(t, rsaKey)
+	tests := []struct {
+		name      string
+		path      string
+		algorithm string
+		wantAlg   string
+		wantErr   string
+	}{
+		{"PKCS #8 RSA defaults to RS256", rsaFile, "", "RS256", ""},
+		{"RSA with PSS", rsaFile, "PS384", "PS384", ""},
+		{"PKCS #1 RSA", writeKeyFile(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)), "", "RS256", ""},
+		{"P-256 defaults to ES256", pkcs8KeyFile(t, p256Key), "", "ES256", ""},
+		{"SEC 1 P-384 defaults to ES384", writeKeyFile(t, "EC PRIVATE KEY", sec1), "", "ES384", ""},
+		{"Ed25519 defaults to EdDSA", pkcs8KeyFile(t, edKey), "", "EdDSA", ""},
+		{"curve mismatch", pkcs8KeyFile(t, p256Key), "ES384", "", "cannot be used with a ECDSA P-256 key"},
+		{"key type mismatch", rsaFile, "ES256", "", "cannot be used with a RSA key"},
+		{"HMAC not supported", rsaFile, "HS256", "", "unsupported signing algorithm"},
+		{"encrypted key", writeKeyFile(t, "ENCRYPTED PRIVATE KEY", []byte("x")), "", "", "encrypted private keys are not supported"},
+		{"not a key", writeKeyFile(t, "CERTIFICATE", []byte("x")), "", "", "no PEM private key found"},
+		{"missing file", filepath.Join(t.TempDir(), "missing.pem"), "", "", "failed to read private key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := LoadClientKey(tt.path, "key-1", tt.algorithm)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadClientKey failed: %v", err)
+			}
+			if key.Algorithm != tt.wantAlg || key.KeyID != "key-1" {
+				t.Errorf("Expected %s with kid key-1, got %s with kid %q", tt.wantAlg, key.Algorithm, key.KeyID)
+			}
+		})
+	}
+}
+
+func TestClientKey_Assertion(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	p256Key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p521Key, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		alg    string
+		signer crypto.Signer
+	}{
+		{"RS256", rsaKey},
+		{"PS256", rsaKey},
+		{"ES256", p256Key},
+		{"ES512", p521Key},
+		{"EdDSA", edKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alg, func(t *testing.T) {
+			key := &ClientKey{Signer: tt.signer, KeyID: "key-1", Algorithm: tt.alg}
+			assertion, err := key.Assertion("my-client", "https://auth.example.com/token")
+			if err != nil {
+				t.Fatalf("Assertion failed: %v", err)
+			}
+
+			header, claims := verifyAssertion(t, assertion, tt.signer.Public())
+			if header["alg"] != tt.alg || header["kid"] != "key-1" || header["typ"] != "JWT" {
+				t.Errorf("Unexpected header: %v", header)
+			}
+			if claims["iss"] != "my-client" || claims["sub"] != "my-client" || claims["aud"] != "https://auth.example.com/token" {
+				t.Errorf("Unexpected claims: %v", claims)
+			}
+			if claims["jti"] == "" {
+				t.Error("Expected a jti claim")
+			}
+			iat, _ := claims["iat"].(float64)
+			exp, _ := claims["exp"].(float64)
+			if lifetime := time.Duration(exp-iat) * time.Second; lifetime <= 0 || lifetime > 5*time.Minute {
+				t.Errorf("Expected a short-lived assertion, got lifetime %v", lifetime)
+			}
+		})
+	}
+}
+
+func TestAuthorizeWithClientCredentials_PrivateKeyJWT(t *testing.T) {
+	signer, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	key := &ClientKey{Signer: signer, KeyID: "key-1", Algorithm: "ES256"}
+
+	var mu sync.Mutex
+	var assertions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if _, _, ok := r.BasicAuth(); ok || r.PostForm.Has("client_secret") {
+			t.Errorf("Expected no client secret with private_key_jwt, got form %v", r.PostForm)
+		}
+		if r.PostForm.Get("client_assertion_type") != ClientAssertionType {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		mu.Lock()
+		assertions = append(assertions, r.PostForm.Get("client_assertion"))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"jwt-token","token_type":"Bearer","expires_in":1}`))
+	}))
+	defer server.Close()
+
+	config := &Config{
+		ClientID:    "my-client",
+		ResourceURI: "https://mcp.example.com/mcp",
+		TokenURL:    server.URL + "/token",
+		FlowType:    FlowTypeClientCredentials,
+		ClientKey:   key,
+	}
+
+	token, err := Authorize(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Authorize failed: %v", err)
+	}
+	if token.AccessToken != "jwt-token" {
+		t.Errorf("Expected jwt-token, got %q", token.AccessToken)
+	}
+
+	// Expired tokens are replaced using a newly signed assertion
+	t.Setenv(configdir.HomeEnv, t.TempDir())
+	rt, err := NewOAuthRoundTripper(nil, config)
+	if err != nil {
+		t.Fatalf("NewOAuthRoundTripper failed: %v", err)
+	}
+	for range 2 {
+		if _, err := rt.getValidToken(context.Background()); err != nil {
+			t.Fatalf("getValidToken failed: %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(assertions) != 3 || assertions[1] == assertions[2] {
+		t.Fatalf("Expected a fresh assertion for each of 3 token requests, got %d", len(assertions))
+	}
+	for _, assertion := range assertions {
+		if _, claims := verifyAssertion(t, assertion, signer.Public()); claims["aud"] != config.TokenURL {
+			t.Errorf("Expected the token endpoint as audience, got %v", claims["aud"])
+		}
+	}
+}
+
+func TestGetOrRegisterClient_PrivateKeyJWT(t *testing.T) {
+	t.Setenv(configdir.HomeEnv, t.TempDir())
+
+	_, signer, _ := ed25519.GenerateKey(rand.Reader)
+	key := &ClientKey{Signer: signer, KeyID: "key-1", Algorithm: "EdDSA"}
+
+	var requests []ClientRegistrationRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ClientRegistrationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Invalid registration request: %v", err)
+		}
+		requests = append(requests, req)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(ClientRegistrationResponse{ClientID: req.TokenEndpointAuthMethod + "-client"})
+	}))
+	defer server.Close()
+
+	resource := "https://mcp.example.com/mcp"
+	public, err := GetOrRegisterClient(context.Background(), resource, server.URL, DefaultScopes(), nil)
+	if err != nil || public.ClientID != "none-client" {
+		t.Fatalf("Expected a public registration, got %+v, %v", public, err)
+	}
+
+	// A cached public registration is not reused once a key is configured
+	confidential, err := GetOrRegisterClient(context.Background(), resource, server.URL, DefaultScopes(), key)
+	if err != nil || confidential.ClientID != "private_key_jwt-client" {
+		t.Fatalf("Expected a private_key_jwt registration, got %+v, %v", confidential, err)
+	}
+	if _, err := GetOrRegisterClient(context.Background(), resource, server.URL, DefaultScopes(), key); err != nil {
+		t.Fatalf("GetOrRegisterClient failed: %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("Expected the private_key_jwt registration to be cached, got %d registrations", len(requests))
+	}
+
+	req := requests[1]
+	if req.TokenEndpointAuthMethod != AuthMethodPrivateKeyJWT || req.TokenEndpointAuthSigningAlg != "EdDSA" {
+		t.Errorf("Expected private_key_jwt with EdDSA, got %s with %s", req.TokenEndpointAuthMethod, req.TokenEndpointAuthSigningAlg)
+	}
+	if req.JWKS == nil || len(req.JWKS.Keys) != 1 {
+		t.Fatalf("Expected the public key in jwks, got %+v", req.JWKS)
+	}
+	jwk := req.JWKS.Keys[0]
+	pub, _ := base64.RawURLEncoding.DecodeString(jwk.X)
+	if jwk.KeyType != "OKP" || jwk.Curve != "Ed25519" || jwk.KeyID != "key-1" || !ed25519.PublicKey(pub).Equal(signer.Public()) {
+		t.Errorf("Unexpected JWK: %+v", jwk)
+	}
+
+	// The cached registration is only reused for the same key and key ID
+	_, otherSigner, _ := ed25519.GenerateKey(rand.Reader)
+	changes := []struct {
+		name string
+		key  *ClientKey
+	}{
+		{"new_key", &ClientKey{Signer: otherSigner, KeyID: "key-1", Algorithm: "EdDSA"}},
+		{"new_key_id", &ClientKey{Signer: otherSigner, KeyID: "key-2", Algorithm: "EdDSA"}},
+	}
+	for _, change := range changes {
+		before := len(requests)
+		registration, err := GetOrRegisterClient(context.Background(), resource, server.URL, DefaultScopes(), change.key)
+		if err != nil {
+			t.Fatalf("%s: GetOrRegisterClient failed: %v", change.name, err)
+		}
+		if len(requests) != before+1 {
+			t.Errorf("%s: Expected a new registration, got %d", change.name, len(requests)-before)
+		}
+		want, _ := change.key.Thumbprint()
+		if registration.KeyThumbprint != want {
+			t.Errorf("%s: Expected the registration to record the key thumbprint", change.name)
+		}
+		if got := requests[len(requests)-1].JWKS.Keys[0].KeyID; got != change.key.KeyID {
+			t.Errorf("%s: Expected kid %q to be registered, got %q", change.name, change.key.KeyID, got)
+		}
+	}
+}
+
+func TestClientKey_PublicJWK(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	jwk, err := (&ClientKey{Signer: ecKey, Algorithm: "ES256"}).PublicJWK()
+	if err != nil {
+		t.Fatalf("PublicJWK failed: %v", err)
+	}
+	x, _ := base64.RawURLEncoding.DecodeString(jwk.X)
+	y, _ := base64.RawURLEncoding.DecodeString(jwk.Y)
+	if jwk.KeyType != "EC" || jwk.Curve != "P-256" || len(x) != 32 || len(y) != 32 {
+		t.Errorf("Unexpected EC JWK: %+v", jwk)
+	}
+	ecdhKey, _ := ecKey.PublicKey.ECDH()
+	if point := append(append([]byte{4}, x...), y...); !bytes.Equal(point, ecdhKey.Bytes()) {
+		t.Error("EC JWK coordinates do not match the key")
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	jwk, err = (&ClientKey{Signer: rsaKey, Algorithm: "RS256"}).PublicJWK()
+	if err != nil {
+		t.Fatalf("PublicJWK failed: %v", err)
+	}
+	n, _ := base64.RawURLEncoding.DecodeString(jwk.N)
+	if jwk.KeyType != "RSA" || jwk.E != "AQAB" || new(big.Int).SetBytes(n).Cmp(rsaKey.N) != 0 {
+		t.Errorf("Unexpected RSA JWK: %+v", jwk)
+	}
+}
@@ -7,16 +7,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/spandigital/mcp-server-dump/internal/configdir"
 )
 
 // RegisterClient performs Dynamic Client Registration (RFC 7591) with the authorization server.
 // It registers the client and returns the client_id and optional client_secret.
-func RegisterClient(ctx context.Context, registrationEndpoint, resourceURI string, scopes []string) (*ClientRegistration, error) {
+// With a client key, the client registers as confidential using private_key_jwt and sends
+// its public key in the jwks parameter; otherwise it registers as a public client.
+func RegisterClient(ctx context.Context, registrationEndpoint, resourceURI string, scopes []string, key *ClientKey) (*ClientRegistration, error) {
 	if registrationEndpoint == "" {
 		return nil, fmt.Errorf("registration endpoint not provided")
 	}
@@ -41,10 +46,26 @@ func RegisterClient(ctx context.Context, registrationEndpoint, resourceURI strin
 			"http://[::1]:8080/callback",     // IPv6 loopback with fixed port
 		},
 		GrantTypes:              grantTypes,
-		TokenEndpointAuthMethod: "none", // Public client (no client secret required)
+		TokenEndpointAuthMethod: AuthMethodNone, // Public client (no client secret required)
 		Scope:                   strings.Join(scopes, " "),
 	}
 
+	// Confidential client authenticating with a signed assertion instead of a secret
+	var thumbprint string
+	if key != nil {
+		jwk, err := key.PublicJWK()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode client public key: %w", err)
+		}
+		if thumbprint, err = key.Thumbprint(); err != nil {
+			return nil, fmt.Errorf("failed to encode client public key: %w", err)
+		}
+		regRequest.GrantTypes = append(regRequest.GrantTypes, "client_credentials")
+		regRequest.TokenEndpointAuthMethod = AuthMethodPrivateKeyJWT
+		regRequest.TokenEndpointAuthSigningAlg = key.Algorithm
+		regRequest.JWKS = &JSONWebKeySet{Keys: []JSONWebKey{jwk}}
+	}
+
 	// Marshal request
 	reqBody, err := json.Marshal(regRequest)
 	if err != nil {
@@ -59,7 +80,7 @@ func RegisterClient(ctx context.Context, registrationEndpoint, resourceURI strin
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := http.DefaultClient.Do(req) //nolint:gosec // G704: URL comes from discovered OAuth registration endpoint
+	resp, err := httpClient(ctx).Do(req) //nolint:gosec // G704: URL comes from discovered OAuth registration endpoint
 	if err != nil {
 		return nil, fmt.Errorf("registration request failed: %w", err)
 	}
@@ -92,6 +113,8 @@ func RegisterClient(ctx context.Context, registrationEndpoint, resourceURI strin
 		ClientID:                regResponse.ClientID,
 		ClientSecret:            regResponse.ClientSecret,
 		RegistrationAccessToken: regResponse.RegistrationAccessToken,
+		TokenEndpointAuthMethod: regRequest.TokenEndpointAuthMethod,
+		KeyThumbprint:           thumbprint,
 		RegisteredAt:            time.Now(),
 	}
 
@@ -100,16 +123,11 @@ func RegisterClient(ctx context.Context, registrationEndpoint, resourceURI strin
 
 // LoadClientRegistration loads a cached client registration for the given resource URI.
 func LoadClientRegistration(resourceURI string) (*ClientRegistration, error) {
-	cacheDir, err := getRegistrationCacheDir()
+	filePath, err := registrationCachePath(resourceURI)
 	if err != nil {
 		return nil, err
 	}
 
-	// Hash the resource URI to create filename
-	hash := sha256.Sum256([]byte(resourceURI))
-	filename := hex.EncodeToString(hash[:]) + ".json"
-	filePath := filepath.Join(cacheDir, filename)
-
 	// Check if file exists
 	if _, statErr := os.Stat(filePath); os.IsNotExist(statErr) {
 		return nil, nil // No cached registration
@@ -136,29 +154,24 @@ func SaveClientRegistration(registration *ClientRegistration) error {
 		return fmt.Errorf("invalid registration")
 	}
 
-	cacheDir, err := getRegistrationCacheDir()
+	filePath, err := registrationCachePath(registration.ResourceURI)
 	if err != nil {
 		return err
 	}
 
 	// Create cache directory if it doesn't exist
-	if mkdirErr := os.MkdirAll(cacheDir, 0o700); mkdirErr != nil {
+	if mkdirErr := os.MkdirAll(filepath.Dir(filePath), 0o700); mkdirErr != nil {
 		return fmt.Errorf("failed to create registration cache directory: %w", mkdirErr)
 	}
 
-	// Hash the resource URI to create filename
-	hash := sha256.Sum256([]byte(registration.ResourceURI))
-	filename := hex.EncodeToString(hash[:]) + ".json"
-	filePath := filepath.Join(cacheDir, filename)
-
 	// Marshal registration
 	data, err := json.MarshalIndent(registration, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal registration: %w", err)
 	}
 
-	// Write file with secure permissions
-	if writeErr := os.WriteFile(filePath, data, 0o600); writeErr != nil {
+	// Write file with secure permissions, replacing it atomically for concurrent readers
+	if writeErr := configdir.WriteFile(filePath, data, 0o600); writeErr != nil {
 		return fmt.Errorf("failed to write registration cache: %w", writeErr)
 	}
 
@@ -167,16 +180,11 @@ func SaveClientRegistration(registration *ClientRegistration) error {
 
 // ClearClientRegistration removes a cached client registration for the given resource URI.
 func ClearClientRegistration(resourceURI string) error {
-	cacheDir, err := getRegistrationCacheDir()
+	filePath, err := registrationCachePath(resourceURI)
 	if err != nil {
 		return err
 	}
 
-	// Hash the resource URI to create filename
-	hash := sha256.Sum256([]byte(resourceURI))
-	filename := hex.EncodeToString(hash[:]) + ".json"
-	filePath := filepath.Join(cacheDir, filename)
-
 	// Remove file (ignore error if file doesn't exist)
 	err = os.Remove(filePath)
 	if err != nil && !os.IsNotExist(err) {
@@ -186,21 +194,95 @@ func ClearClientRegistration(resourceURI string) error {
 	return nil
 }
 
-// getRegistrationCacheDir returns the directory for cached client registrations.
+// ClearAllClientRegistrations removes all cached client registrations.
+func ClearAllClientRegistrations() error {
+	cacheDir, err := getRegistrationCacheDir()
+	if err != nil {
+		return err
+	}
+
+	// Remove entire registrations directory
+	if err := os.RemoveAll(cacheDir); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove registration cache directory: %w", err)
+	}
+
+	return nil
+}
+
+// ListRegisteredServers returns a list of resource URIs that have cached client registrations.
+func ListRegisteredServers() ([]string, error) {
+	cacheDir, err := getRegistrationCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if directory exists
+	if _, statErr := os.Stat(cacheDir); os.IsNotExist(statErr) {
+		return []string{}, nil // No cache directory means no registrations
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registration cache directory: %w", err)
+	}
+
+	servers := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		// #nosec G304 - path is from directory listing, controlled by filesystem
+		data, fileErr := os.ReadFile(filepath.Join(cacheDir, entry.Name()))
+		if fileErr != nil {
+			continue // Skip invalid files
+		}
+
+		var registration ClientRegistration
+		if err := json.Unmarshal(data, &registration); err != nil || registration.ResourceURI == "" {
+			continue // Skip invalid JSON
+		}
+
+		servers = append(servers, registration.ResourceURI)
+	}
+
+	return servers, nil
+}
+
+// getRegistrationCacheDir returns the directory for cached client registrations:
+// registrations/ in the configuration directory (see configdir.Dir).
 func getRegistrationCacheDir() (string, error) {
-	homeDir, err := os.UserHomeDir()
+	return configdir.Sub("registrations")
+}
+
+// registrationCachePath returns the cache file for a registration, named by the SHA-256 hash
+// of the resource URI
+func registrationCachePath(resourceURI string) (string, error) {
+	cacheDir, err := getRegistrationCacheDir()
 	if err != nil {
-		return "", fmt.Errorf("failed to get user home directory: %w", err)
+		return "", err
 	}
 
-	// Use ~/.config/mcp-server-dump/registrations/
-	cacheDir := filepath.Join(homeDir, ".config", "mcp-server-dump", "registrations")
-	return cacheDir, nil
+	hash := sha256.Sum256([]byte(resourceURI))
+	return filepath.Join(cacheDir, hex.EncodeToString(hash[:])+".json"), nil
 }
 
 // GetOrRegisterClient gets a cached client registration or performs DCR if needed.
-// This is the main entry point for automatic client registration.
-func GetOrRegisterClient(ctx context.Context, resourceURI, registrationEndpoint string, scopes []string) (*ClientRegistration, error) {
+// This is the main entry point for automatic client registration. A cached registration
+// is only reused if it was made with the same client authentication method and, for
+// private_key_jwt, the same key, key ID and algorithm.
+func GetOrRegisterClient(ctx context.Context, resourceURI, registrationEndpoint string, scopes []string, key *ClientKey) (*ClientRegistration, error) {
+	// Hold the registration lock so concurrent processes register the client only once
+	cachePath, err := registrationCachePath(resourceURI)
+	if err != nil {
+		return nil, err
+	}
+	unlock, err := configdir.Lock(cachePath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	// Try to load from cache first
 	cached, err := LoadClientRegistration(resourceURI)
 	if err != nil {
@@ -208,24 +290,35 @@ func GetOrRegisterClient(ctx context.Context, resourceURI, registrationEndpoint
 		fmt.Fprintf(os.Stderr, "Warning: failed to load cached registration: %v\n", err)
 	}
 
-	if cached != nil && cached.ClientID != "" {
+	var thumbprint string
+	if key != nil {
+		if thumbprint, err = key.Thumbprint(); err != nil {
+			return nil, fmt.Errorf("failed to encode client public key: %w", err)
+		}
+	}
+
+	if cached != nil && cached.ClientID != "" && registrationAuthMethod(cached) == registrationAuthMethodFor(key) &&
+		cached.KeyThumbprint == thumbprint {
 		// Found cached registration
 		return cached, nil
 	}
+	if cached != nil && cached.KeyThumbprint != "" && key != nil && cached.KeyThumbprint != thumbprint {
+		log.Printf("Client key changed since the cached registration, registering again")
+	}
 
 	// No cached registration - perform DCR
 	if registrationEndpoint == "" {
 		return nil, fmt.Errorf("no cached registration and server does not support Dynamic Client Registration")
 	}
 
-	fmt.Printf("Registering client with authorization server...\n")
-	registration, err := RegisterClient(ctx, registrationEndpoint, resourceURI, scopes)
+	log.Printf("Registering client with authorization server...")
+	registration, err := RegisterClient(ctx, registrationEndpoint, resourceURI, scopes, key)
 	if err != nil {
 		return nil, fmt.Errorf("dynamic client registration failed: %w", err)
 	}
 
-	fmt.Printf("✓ Client registered successfully\n")
-	fmt.Printf("  Client ID: %s\n", registration.ClientID)
+	log.Printf("✓ Client registered successfully")
+	log.Printf("  Client ID: %s", registration.ClientID)
 
 	// Save to cache
 	if saveErr := SaveClientRegistration(registration); saveErr != nil {
@@ -235,3 +328,20 @@ func GetOrRegisterClient(ctx context.Context, resourceURI, registrationEndpoint
 
 	return registration, nil
 }
+
+// registrationAuthMethod returns the authentication method of a registration, which is none
+// for registrations cached before the method was recorded
+func registrationAuthMethod(registration *ClientRegistration) string {
+	if registration.TokenEndpointAuthMethod == "" {
+		return AuthMethodNone
+	}
+	return registration.TokenEndpointAuthMethod
+}
+
+// registrationAuthMethodFor returns the authentication method RegisterClient uses for key
+func registrationAuthMethodFor(key *ClientKey) string {
+	if key != nil {
+		return AuthMethodPrivateKeyJWT
+	}
+	return AuthMethodNone
+}
@@ -43,7 +43,7 @@ func NewOAuthRoundTripper(base http.RoundTripper, config *Config) (*OAuthRoundTr
 
 	// Try to load cached token if caching is enabled
 	if config.UseCache {
-		cached, err := LoadToken(config.ResourceURI)
+		cached, err := config.tokenStore().Load(config.ResourceURI)
 		if err == nil && cached != nil {
 			rt.token = cached.ToOAuth2Token()
 
@@ -111,18 +111,12 @@ func (rt *OAuthRoundTripper) getValidToken(ctx context.Context) (*oauth2.Token,
 	if tokenSource != nil {
 		newToken, err := tokenSource.Token()
 		if err == nil {
-			// Update stored token if it changed (check both access token and expiry)
+			// Update stored token if it changed (check both access token and expiry).
+			// With caching enabled, the token source has already saved it.
 			if token == nil || newToken.AccessToken != token.AccessToken || newToken.Expiry != token.Expiry {
 				rt.mu.Lock()
 				rt.token = newToken
 				rt.mu.Unlock()
-
-				// Save to cache if enabled
-				if rt.config.UseCache {
-					if saveErr := SaveToken(newToken, rt.config.ResourceURI, rt.config.Scopes); saveErr != nil {
-						_, _ = fmt.Fprintf(os.Stderr, "Warning: failed to save refreshed token to cache: %v\n", saveErr)
-					}
-				}
 			}
 			return newToken, nil
 		}
@@ -158,8 +152,8 @@ func (rt *OAuthRoundTripper) performOAuthFlow(ctx context.Context) (*oauth2.Toke
 	rt.initializing = true
 	rt.mu.Unlock()
 
-	// Perform OAuth flow
-	token, err := Authorize(ctx, rt.config)
+	// Perform OAuth flow, sending token requests through the base transport
+	token, err := Authorize(WithHTTPTransport(ctx, rt.base), rt.config)
 
 	rt.mu.Lock()
 	defer rt.mu.Unlock()
@@ -182,7 +176,7 @@ func (rt *OAuthRoundTripper) performOAuthFlow(ctx context.Context) (*oauth2.Toke
 
 	// Save to cache if enabled
 	if rt.config.UseCache {
-		if saveErr := SaveToken(token, rt.config.ResourceURI, rt.config.Scopes); saveErr != nil {
+		if saveErr := rt.config.saveToken(token); saveErr != nil {
 			// Log error but don't fail the request
 			_, _ = fmt.Fprintf(os.Stderr, "Warning: failed to save token to cache: %v\n", saveErr)
 		}
@@ -198,11 +192,26 @@ func (rt *OAuthRoundTripper) performOAuthFlow(ctx context.Context) (*oauth2.Toke
 // Token refresh operations won't be cancelled by request context cancellation, which is
 // acceptable for this use case as tokens are cached and reused across requests.
 func (rt *OAuthRoundTripper) createTokenSource(token *oauth2.Token) oauth2.TokenSource {
+	if rt.config.UseCache {
+		return oauth2.ReuseTokenSource(token, &cachedTokenSource{config: rt.config, token: token, newSource: rt.refreshTokenSource})
+	}
+	return oauth2.ReuseTokenSource(token, rt.refreshTokenSource(token))
+}
+
+// refreshTokenSource returns the source of a new token once token has expired
+func (rt *OAuthRoundTripper) refreshTokenSource(token *oauth2.Token) oauth2.TokenSource {
+	// Client credentials tokens are not refreshed; a new one is requested when the current one expires
+	if determineFlowType(rt.config) == FlowTypeClientCredentials {
+		ctx := clientCredentialsContext(WithHTTPTransport(context.Background(), rt.base), rt.config)
+		return clientCredentialsConfig(rt.config).TokenSource(ctx)
+	}
+
 	oauth2Config := &oauth2.Config{
 		ClientID:     rt.config.ClientID,
 		ClientSecret: rt.config.ClientSecret,
 		Endpoint: oauth2.Endpoint{
-			TokenURL: rt.config.TokenURL,
+			TokenURL:  rt.config.TokenURL,
+			AuthStyle: rt.config.authStyle(),
 		},
 	}
 
@@ -210,11 +219,54 @@ func (rt *OAuthRoundTripper) createTokenSource(token *oauth2.Token) oauth2.Token
 	// Uses Background() because TokenSource is long-lived and context is only for client config
 	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{
 		Transport: &resourceParamTransport{
-			base:     http.DefaultTransport,
+			base:     rt.config.tokenTransport(rt.base),
 			resource: rt.config.ResourceURI,
 		},
 	})
 
-	// Create token source that auto-refreshes
-	return oauth2.ReuseTokenSource(token, oauth2Config.TokenSource(ctx, token))
+	// Create token source that refreshes with the refresh token
+	return oauth2Config.TokenSource(ctx, token)
+}
+
+// cachedTokenSource replaces an expired token while holding the lock on its cache entry,
+// so processes sharing the token cache (such as concurrent CI jobs) don't refresh at once.
+// A token refreshed by another process while the lock was awaited is used as is; otherwise
+// the newest cached token is refreshed, as another process may have rotated its refresh token.
+type cachedTokenSource struct {
+	config    *Config
+	newSource func(*oauth2.Token) oauth2.TokenSource
+
+	mu    sync.Mutex
+	token *oauth2.Token // the newest token seen, whose refresh token is used
+}
+
+// Token implements oauth2.TokenSource
+func (s *cachedTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := lockTokenCache(s.config.ResourceURI)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	store := s.config.tokenStore()
+	if cached, loadErr := store.Load(s.config.ResourceURI); loadErr == nil && cached != nil {
+		s.token = cached.ToOAuth2Token()
+		if s.token.Valid() {
+			return s.token, nil
+		}
+	}
+
+	token, err := s.newSource(s.token).Token()
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+
+	if saveErr := s.config.saveToken(token); saveErr != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: failed to save refreshed token to cache: %v\n", saveErr)
+	}
+	return token, nil
 }
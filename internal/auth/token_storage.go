@@ -9,39 +9,55 @@ import (
 	"path/filepath"
 
 	"golang.org/x/oauth2"
+
+	"github.com/spandigital/mcp-server-dump/internal/configdir"
 )
 
-// tokenCacheDir returns the directory path for token cache files.
-// Uses XDG Base Directory specification: ~/.config/mcp-server-dump/tokens/
+// tokenCacheDir returns the directory path for token cache files: tokens/ in the
+// configuration directory, which follows XDG_CONFIG_HOME (see configdir.Dir).
 func tokenCacheDir() (string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get user home directory: %w", err)
-	}
+	return configdir.Sub("tokens")
+}
 
-	// Use XDG Base Directory: ~/.config/mcp-server-dump/tokens/
-	cacheDir := filepath.Join(homeDir, ".config", "mcp-server-dump", "tokens")
-	return cacheDir, nil
+// tokenCacheName returns the file name stem for a cached token: the first 8 bytes of the
+// SHA-256 hash of the resource URI, hex encoded
+func tokenCacheName(resourceURI string) string {
+	hash := sha256.Sum256([]byte(resourceURI))
+	return hex.EncodeToString(hash[:8])
 }
 
 // tokenCachePath returns the file path for a cached token based on the resource URI.
-// Tokens are stored as: ~/.config/mcp-server-dump/tokens/<hash>.json
+// Tokens are stored as: <config dir>/tokens/<hash>.json
 func tokenCachePath(resourceURI string) (string, error) {
 	dir, err := tokenCacheDir()
 	if err != nil {
 		return "", err
 	}
 
-	// Create hash of resource URI for filename
-	hash := sha256.Sum256([]byte(resourceURI))
-	filename := hex.EncodeToString(hash[:8]) + ".json" // Use first 8 bytes of hash
+	return filepath.Join(dir, tokenCacheName(resourceURI)+".json"), nil
+}
 
-	return filepath.Join(dir, filename), nil
+// lockTokenCache takes the cross-process lock on the cached token for resourceURI. The lock
+// is shared by every token store, as it guards the server's token rather than a file.
+func lockTokenCache(resourceURI string) (unlock func(), err error) {
+	dir, err := tokenCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return configdir.Lock(filepath.Join(dir, tokenCacheName(resourceURI)))
 }
 
-// LoadToken loads a cached OAuth token for the specified MCP server endpoint.
-// Returns nil if no cached token exists or if the token file is invalid.
-func LoadToken(resourceURI string) (*TokenCache, error) {
+// fileTokenStore is the TokenStore that keeps each token in a plaintext JSON file
+// readable only by the owner
+type fileTokenStore struct{}
+
+// Name implements TokenStore
+func (fileTokenStore) Name() string {
+	return TokenStoreFile
+}
+
+// Load implements TokenStore
+func (fileTokenStore) Load(resourceURI string) (*TokenCache, error) {
 	cachePath, err := tokenCachePath(resourceURI)
 	if err != nil {
 		return nil, err
@@ -73,13 +89,9 @@ func LoadToken(resourceURI string) (*TokenCache, error) {
 	return &cache, nil
 }
 
-// SaveToken saves an OAuth token to the cache for the specified MCP server endpoint.
-// Creates the cache directory if it doesn't exist and sets appropriate file permissions (0600).
-func SaveToken(token *oauth2.Token, resourceURI string, scopes []string) error {
-	if token == nil {
-		return fmt.Errorf("token cannot be nil")
-	}
-
+// Save implements TokenStore. It creates the cache directory if it doesn't exist and sets
+// appropriate file permissions (0600).
+func (fileTokenStore) Save(cache *TokenCache) error {
 	// Create cache directory if it doesn't exist
 	cacheDir, err := tokenCacheDir()
 	if err != nil {
@@ -90,9 +102,6 @@ func SaveToken(token *oauth2.Token, resourceURI string, scopes []string) error {
 		return fmt.Errorf("failed to create token cache directory: %w", mkdirErr)
 	}
 
-	// Convert to TokenCache
-	cache := FromOAuth2Token(token, resourceURI, scopes)
-
 	// Marshal to JSON
 	data, err := json.MarshalIndent(cache, "", "  ")
 	if err != nil {
@@ -100,21 +109,21 @@ func SaveToken(token *oauth2.Token, resourceURI string, scopes []string) error {
 	}
 
 	// Get cache file path
-	cachePath, err := tokenCachePath(resourceURI)
+	cachePath, err := tokenCachePath(cache.ResourceURI)
 	if err != nil {
 		return err
 	}
 
-	// Write to file with restricted permissions (owner read/write only)
-	if err := os.WriteFile(cachePath, data, 0o600); err != nil { //nolint:gosec // G703: path is constructed from SHA256 hash of resource URI, not directly from user input
+	// Write to file with restricted permissions (owner read/write only), replacing it atomically
+	if err := configdir.WriteFile(cachePath, data, 0o600); err != nil {
 		return fmt.Errorf("failed to write token cache: %w", err)
 	}
 
 	return nil
 }
 
-// ClearToken removes the cached token for the specified MCP server endpoint.
-func ClearToken(resourceURI string) error {
+// Delete implements TokenStore
+func (fileTokenStore) Delete(resourceURI string) error {
 	cachePath, err := tokenCachePath(resourceURI)
 	if err != nil {
 		return err
@@ -128,23 +137,14 @@ func ClearToken(resourceURI string) error {
 	return nil
 }
 
-// ClearAllTokens removes all cached tokens.
-func ClearAllTokens() error {
-	cacheDir, err := tokenCacheDir()
-	if err != nil {
-		return err
-	}
-
-	// Remove entire tokens directory
-	if err := os.RemoveAll(cacheDir); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to remove token cache directory: %w", err)
-	}
-
-	return nil
+// DeleteAll implements TokenStore. Only plaintext token files are removed, so tokens kept in
+// the same directory by the encrypted store survive.
+func (fileTokenStore) DeleteAll() error {
+	return removeTokenFiles(".json")
 }
 
-// ListCachedServers returns a list of resource URIs that have cached tokens.
-func ListCachedServers() ([]string, error) {
+// List implements TokenStore
+func (fileTokenStore) List() ([]string, error) {
 	cacheDir, err := tokenCacheDir()
 	if err != nil {
 		return nil, err
@@ -185,3 +185,60 @@ func ListCachedServers() ([]string, error) {
 
 	return servers, nil
 }
+
+// removeTokenFiles removes the files with the given extension from the token cache directory
+func removeTokenFiles(ext string) error {
+	cacheDir, err := tokenCacheDir()
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read token cache directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ext {
+			continue
+		}
+		if err := os.Remove(filepath.Join(cacheDir, entry.Name())); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove token cache: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// LoadToken loads a cached OAuth token for the specified MCP server endpoint from the file store.
+// Returns nil if no cached token exists or if the token file is invalid.
+func LoadToken(resourceURI string) (*TokenCache, error) {
+	return fileTokenStore{}.Load(resourceURI)
+}
+
+// SaveToken saves an OAuth token to the file store for the specified MCP server endpoint.
+// Creates the cache directory if it doesn't exist and sets appropriate file permissions (0600).
+func SaveToken(token *oauth2.Token, resourceURI string, scopes []string) error {
+	if token == nil {
+		return fmt.Errorf("token cannot be nil")
+	}
+	return fileTokenStore{}.Save(FromOAuth2Token(token, resourceURI, scopes))
+}
+
+// ClearToken removes the cached token for the specified MCP server endpoint from the file store.
+func ClearToken(resourceURI string) error {
+	return fileTokenStore{}.Delete(resourceURI)
+}
+
+// ClearAllTokens removes all tokens cached in the file store.
+func ClearAllTokens() error {
+	return fileTokenStore{}.DeleteAll()
+}
+
+// ListCachedServers returns a list of resource URIs that have tokens in the file store.
+func ListCachedServers() ([]string, error) {
+	return fileTokenStore{}.List()
+}
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"golang.org/x/oauth2"
+)
+
+// Token store backends selectable with NewTokenStore
+const (
+	// TokenStoreFile keeps tokens in plaintext JSON files readable only by the owner
+	TokenStoreFile = "file"
+
+	// TokenStoreEncrypted keeps tokens in files encrypted with a key or passphrase from the environment
+	TokenStoreEncrypted = "encrypted"
+
+	// TokenStoreKeyring keeps tokens in the OS keyring (requires building with -tags keyring)
+	TokenStoreKeyring = "keyring"
+)
+
+// TokenStore persists cached OAuth tokens, keyed by the MCP server resource URI.
+type TokenStore interface {
+	// Name returns the backend name, one of the TokenStore constants
+	Name() string
+
+	// Load returns the cached token for resourceURI, or nil if there is none
+	Load(resourceURI string) (*TokenCache, error)
+
+	// Save stores the token under its ResourceURI, replacing any previous token
+	Save(cache *TokenCache) error
+
+	// Delete removes the token for resourceURI; a missing token is not an error
+	Delete(resourceURI string) error
+
+	// DeleteAll removes every token in the store
+	DeleteAll() error
+
+	// List returns the resource URIs that have a cached token
+	List() ([]string, error)
+}
+
+// NewTokenStore opens the named token store. Tokens cached in plaintext files by the file
+// store are moved into any other store, so switching to a more secure store leaves no
+// plaintext copies behind.
+func NewTokenStore(name string) (TokenStore, error) {
+	var store TokenStore
+	switch name {
+	case "", TokenStoreFile:
+		return fileTokenStore{}, nil
+	case TokenStoreEncrypted:
+		encrypted, err := newEncryptedTokenStore()
+		if err != nil {
+			return nil, err
+		}
+		store = encrypted
+	case TokenStoreKeyring:
+		keyring, err := newKeyringTokenStore()
+		if err != nil {
+			return nil, err
+		}
+		store = keyring
+	default:
+		return nil, fmt.Errorf("unknown token store %q (use %s, %s or %s)", name, TokenStoreFile, TokenStoreEncrypted, TokenStoreKeyring)
+	}
+
+	migrated, err := MigrateTokens(fileTokenStore{}, store)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate cached tokens to the %s token store: %w", store.Name(), err)
+	}
+	if migrated > 0 {
+		// Logged to stderr, as this runs during dumps whose output may go to stdout
+		log.Printf("✓ Migrated %d cached token(s) to the %s token store", migrated, store.Name())
+	}
+
+	return store, nil
+}
+
+// MigrateTokens moves every token from one store to another and returns how many were moved.
+// Each token is deleted from the source only after it has been saved to the destination.
+func MigrateTokens(from, to TokenStore) (int, error) {
+	servers, err := from.List()
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for _, server := range servers {
+		cache, err := from.Load(server)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping cached token for %s: %v\n", server, err)
+			continue
+		}
+		if cache == nil {
+			continue
+		}
+		if err := to.Save(cache); err != nil {
+			return migrated, err
+		}
+		if err := from.Delete(server); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+
+	return migrated, nil
+}
+
+// tokenStore returns the configured token store, defaulting to the file store
+func (c *Config) tokenStore() TokenStore {
+	if c.TokenStore == nil {
+		return fileTokenStore{}
+	}
+	return c.TokenStore
+}
+
+// saveToken caches token in the configured token store
+func (c *Config) saveToken(token *oauth2.Token) error {
+	return c.tokenStore().Save(FromOAuth2Token(token, c.ResourceURI, c.Scopes))
+}
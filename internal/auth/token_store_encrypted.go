@@ -0,0 +1,284 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spandigital/mcp-server-dump/internal/configdir"
+)
+
+// Environment variables holding the encrypted token store secret
+const (
+	// TokenKeyEnv holds a base64-encoded 32-byte AES key
+	TokenKeyEnv = "MCP_SERVER_DUMP_TOKEN_KEY"
+
+	// TokenPassphraseEnv holds a passphrase the AES key is derived from
+	TokenPassphraseEnv = "MCP_SERVER_DUMP_TOKEN_PASSPHRASE" //nolint:gosec // G101: environment variable name, not a credential
+)
+
+// pbkdf2Iterations follows the OWASP recommendation for PBKDF2-HMAC-SHA256
+const pbkdf2Iterations = 600000
+
+// encryptedToken is the on-disk form of a token in the encrypted store
+type encryptedToken struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"` // "none" for a raw key, "pbkdf2-sha256" for a passphrase
+	Iterations int    `json:"iterations,omitempty"`
+	Salt       []byte `json:"salt,omitempty"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// encryptedTokenStore is the TokenStore that keeps each token in a file encrypted with
+// AES-256-GCM, next to the plaintext files of the file store but with a .enc extension
+type encryptedTokenStore struct {
+	key        []byte // raw key from TokenKeyEnv, if set
+	passphrase string // passphrase from TokenPassphraseEnv, used when key is nil
+
+	mu          sync.Mutex
+	derivedKeys map[string][]byte // passphrase keys by salt, so each is derived once
+}
+
+// newEncryptedTokenStore opens the encrypted store with the key or passphrase from the environment
+func newEncryptedTokenStore() (*encryptedTokenStore, error) {
+	store := &encryptedTokenStore{derivedKeys: map[string][]byte{}}
+
+	if encoded := strings.TrimSpace(os.Getenv(TokenKeyEnv)); encoded != "" {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("%s must be a base64-encoded 32-byte key (e.g. from openssl rand -base64 32)", TokenKeyEnv)
+		}
+		store.key = key
+		return store, nil
+	}
+
+	store.passphrase = os.Getenv(TokenPassphraseEnv)
+	if store.passphrase == "" {
+		return nil, fmt.Errorf("the encrypted token store needs %s or %s to be set", TokenKeyEnv, TokenPassphraseEnv)
+	}
+	return store, nil
+}
+
+// Name implements TokenStore
+func (s *encryptedTokenStore) Name() string {
+	return TokenStoreEncrypted
+}
+
+// path returns the encrypted file for resourceURI
+func (s *encryptedTokenStore) path(resourceURI string) (string, error) {
+	dir, err := tokenCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, tokenCacheName(resourceURI)+".enc"), nil
+}
+
+// Load implements TokenStore
+func (s *encryptedTokenStore) Load(resourceURI string) (*TokenCache, error) {
+	path, err := s.path(resourceURI)
+	if err != nil {
+		return nil, err
+	}
+
+	cache, err := s.readFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil // No cached token
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate resource URI matches
+	if cache.ResourceURI != resourceURI {
+		return nil, fmt.Errorf("token cache resource URI mismatch")
+	}
+	return cache, nil
+}
+
+// readFile decrypts the token in path
+func (s *encryptedTokenStore) readFile(path string) (*TokenCache, error) {
+	// #nosec G304 - path is derived from the hashed resource URI or a directory listing
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope encryptedToken
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse encrypted token cache: %w", err)
+	}
+	if envelope.Version != 1 {
+		return nil, fmt.Errorf("unsupported encrypted token cache version %d", envelope.Version)
+	}
+
+	key, err := s.keyFor(&envelope)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid encrypted token cache nonce")
+	}
+
+	// The file name is authenticated so tokens cannot be swapped between servers
+	plaintext, err := aead.Open(nil, envelope.Nonce, envelope.Ciphertext, []byte(filepath.Base(path)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt token cache (wrong %s or %s?)", TokenKeyEnv, TokenPassphraseEnv)
+	}
+
+	var cache TokenCache
+	if err := json.Unmarshal(plaintext, &cache); err != nil {
+		return nil, fmt.Errorf("failed to parse token cache: %w", err)
+	}
+	return &cache, nil
+}
+
+// Save implements TokenStore
+func (s *encryptedTokenStore) Save(cache *TokenCache) error {
+	path, err := s.path(cache.ResourceURI)
+	if err != nil {
+		return err
+	}
+	if mkdirErr := os.MkdirAll(filepath.Dir(path), 0o700); mkdirErr != nil {
+		return fmt.Errorf("failed to create token cache directory: %w", mkdirErr)
+	}
+
+	plaintext, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token cache: %w", err)
+	}
+
+	envelope := encryptedToken{Version: 1, KDF: "none"}
+	if s.key == nil {
+		envelope.KDF = "pbkdf2-sha256"
+		envelope.Iterations = pbkdf2Iterations
+		envelope.Salt = make([]byte, 16)
+		if _, err := rand.Read(envelope.Salt); err != nil {
+			return fmt.Errorf("failed to generate salt: %w", err)
+		}
+	}
+
+	key, err := s.keyFor(&envelope)
+	if err != nil {
+		return err
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	envelope.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(envelope.Nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	envelope.Ciphertext = aead.Seal(nil, envelope.Nonce, plaintext, []byte(filepath.Base(path)))
+
+	data, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal encrypted token cache: %w", err)
+	}
+	if err := configdir.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	return nil
+}
+
+// keyFor returns the AES key for an envelope, deriving it from the passphrase when needed
+func (s *encryptedTokenStore) keyFor(envelope *encryptedToken) ([]byte, error) {
+	switch envelope.KDF {
+	case "none":
+		if s.key == nil {
+			return nil, fmt.Errorf("token cache was encrypted with a key; set %s", TokenKeyEnv)
+		}
+		return s.key, nil
+	case "pbkdf2-sha256":
+		if s.passphrase == "" {
+			return nil, fmt.Errorf("token cache was encrypted with a passphrase; set %s", TokenPassphraseEnv)
+		}
+		if envelope.Iterations <= 0 || len(envelope.Salt) == 0 {
+			return nil, fmt.Errorf("invalid encrypted token cache key derivation parameters")
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		cacheKey := fmt.Sprintf("%d:%x", envelope.Iterations, envelope.Salt)
+		if key, ok := s.derivedKeys[cacheKey]; ok {
+			return key, nil
+		}
+		key, err := pbkdf2.Key(sha256.New, s.passphrase, envelope.Salt, envelope.Iterations, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive token cache key: %w", err)
+		}
+		s.derivedKeys[cacheKey] = key
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported token cache key derivation %q", envelope.KDF)
+	}
+}
+
+// newGCM returns AES-GCM for a 32-byte key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Delete implements TokenStore
+func (s *encryptedTokenStore) Delete(resourceURI string) error {
+	path, err := s.path(resourceURI)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove token cache: %w", err)
+	}
+	return nil
+}
+
+// DeleteAll implements TokenStore
+func (s *encryptedTokenStore) DeleteAll() error {
+	return removeTokenFiles(".enc")
+}
+
+// List implements TokenStore. Tokens that cannot be decrypted are skipped.
+func (s *encryptedTokenStore) List() ([]string, error) {
+	dir, err := tokenCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []string{}, nil // No cache directory means no tokens
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token cache directory: %w", err)
+	}
+
+	servers := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".enc" {
+			continue
+		}
+		cache, err := s.readFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue // Skip unreadable files
+		}
+		servers = append(servers, cache.ResourceURI)
+	}
+	return servers, nil
+}
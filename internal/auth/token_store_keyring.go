@@ -0,0 +1,293 @@
+//go:build keyring
+
+package auth
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/spandigital/mcp-server-dump/internal/configdir"
+)
+
+// keyringService names the keyring entries holding cached tokens
+const keyringService = "mcp-server-dump"
+
+// keyringBackend reads and writes secrets in the OS keyring. Entries are keyed by account,
+// the hashed resource URI, so server URLs are not exposed in keyring attributes.
+type keyringBackend interface {
+	set(account, secret string) error
+	get(account string) (secret string, found bool, err error)
+	delete(account string) error
+}
+
+// keyringTokenStore is the TokenStore that keeps tokens in the OS keyring: the Secret Service
+// (via secret-tool) on Linux and the login keychain (via security) on macOS. The keyring
+// cannot be enumerated portably, so the resource URIs with a stored token are tracked in an
+// index file that holds no secrets.
+type keyringTokenStore struct {
+	backend keyringBackend
+	mu      sync.Mutex // guards the index file within the process; configdir.Lock guards it between processes
+}
+
+// newKeyringTokenStore opens the keyring of the current platform
+func newKeyringTokenStore() (TokenStore, error) {
+	var backend keyringBackend
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if _, err := exec.LookPath("secret-tool"); err != nil {
+			return nil, errors.New("keyring token store requires secret-tool (libsecret-tools) on the PATH")
+		}
+		backend = secretToolBackend{}
+	case "darwin":
+		if _, err := exec.LookPath("security"); err != nil {
+			return nil, errors.New("keyring token store requires the security command on the PATH")
+		}
+		backend = securityBackend{}
+	default:
+		return nil, fmt.Errorf("keyring token store is not supported on %s", runtime.GOOS)
+	}
+	return &keyringTokenStore{backend: backend}, nil
+}
+
+// Name implements TokenStore
+func (s *keyringTokenStore) Name() string {
+	return TokenStoreKeyring
+}
+
+// Load implements TokenStore
+func (s *keyringTokenStore) Load(resourceURI string) (*TokenCache, error) {
+	secret, found, err := s.backend.get(tokenCacheName(resourceURI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token from keyring: %w", err)
+	}
+	if !found {
+		return nil, nil // No cached token
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(secret))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode keyring token: %w", err)
+	}
+	var cache TokenCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("failed to parse token cache: %w", err)
+	}
+
+	// Validate resource URI matches
+	if cache.ResourceURI != resourceURI {
+		return nil, fmt.Errorf("token cache resource URI mismatch")
+	}
+	return &cache, nil
+}
+
+// Save implements TokenStore
+func (s *keyringTokenStore) Save(cache *TokenCache) error {
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token cache: %w", err)
+	}
+	// Base64 keeps the secret on a single line for the keyring tools
+	if err := s.backend.set(tokenCacheName(cache.ResourceURI), base64.StdEncoding.EncodeToString(data)); err != nil {
+		return fmt.Errorf("failed to write token to keyring: %w", err)
+	}
+	return s.updateIndex(func(servers []string) []string {
+		if slices.Contains(servers, cache.ResourceURI) {
+			return servers
+		}
+		return append(servers, cache.ResourceURI)
+	})
+}
+
+// Delete implements TokenStore
+func (s *keyringTokenStore) Delete(resourceURI string) error {
+	if err := s.backend.delete(tokenCacheName(resourceURI)); err != nil {
+		return fmt.Errorf("failed to remove token from keyring: %w", err)
+	}
+	return s.updateIndex(func(servers []string) []string {
+		return slices.DeleteFunc(servers, func(server string) bool { return server == resourceURI })
+	})
+}
+
+// DeleteAll implements TokenStore
+func (s *keyringTokenStore) DeleteAll() error {
+	servers, err := s.List()
+	if err != nil {
+		return err
+	}
+	for _, server := range servers {
+		if err := s.Delete(server); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List implements TokenStore
+func (s *keyringTokenStore) List() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return readKeyringIndex()
+}
+
+// updateIndex rewrites the index of resource URIs with a token in the keyring
+func (s *keyringTokenStore) updateIndex(update func([]string) []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := keyringIndexPath()
+	if err != nil {
+		return err
+	}
+	unlock, err := configdir.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	servers, err := readKeyringIndex()
+	if err != nil {
+		return err
+	}
+	if mkdirErr := os.MkdirAll(filepath.Dir(path), 0o700); mkdirErr != nil {
+		return fmt.Errorf("failed to create keyring index directory: %w", mkdirErr)
+	}
+	data, err := json.MarshalIndent(update(servers), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal keyring index: %w", err)
+	}
+	if err := configdir.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write keyring index: %w", err)
+	}
+	return nil
+}
+
+// keyringIndexPath returns the index file, keyring-tokens.json in the configuration directory
+func keyringIndexPath() (string, error) {
+	dir, err := configdir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "keyring-tokens.json"), nil
+}
+
+// readKeyringIndex returns the resource URIs in the index file
+func readKeyringIndex() ([]string, error) {
+	path, err := keyringIndexPath()
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G304 - path is a fixed location under the config directory
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyring index: %w", err)
+	}
+	var servers []string
+	if err := json.Unmarshal(data, &servers); err != nil {
+		return nil, fmt.Errorf("failed to parse keyring index: %w", err)
+	}
+	return servers, nil
+}
+
+// secretToolBackend stores secrets in the Secret Service with libsecret's secret-tool
+type secretToolBackend struct{}
+
+func (secretToolBackend) set(account, secret string) error {
+	// secret-tool reads the secret from stdin, keeping it out of the process arguments
+	cmd := exec.Command("secret-tool", "store", "--label=mcp-server-dump OAuth token", "service", keyringService, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	return runKeyringCommand(cmd)
+}
+
+func (secretToolBackend) get(account string) (string, bool, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "lookup", "service", keyringService, "account", account)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		// secret-tool exits with status 1 and no output when nothing matches
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && stderr.Len() == 0 {
+			return "", false, nil
+		}
+		return "", false, commandError(err, &stderr)
+	}
+	return stdout.String(), true, nil
+}
+
+func (secretToolBackend) delete(account string) error {
+	cmd := exec.Command("secret-tool", "clear", "service", keyringService, "account", account)
+	err := runKeyringCommand(cmd)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return nil // Nothing to clear
+	}
+	return err
+}
+
+// securityBackend stores secrets in the macOS login keychain with the security command
+type securityBackend struct{}
+
+// securityItemNotFound is the exit status of security when no keychain item matches
+const securityItemNotFound = 44
+
+func (securityBackend) set(account, secret string) error {
+	// In interactive mode security reads commands from stdin, keeping the secret out of the
+	// process arguments. The account is hex and the secret base64, so neither needs quoting.
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", keyringService, account, secret))
+	return runKeyringCommand(cmd)
+}
+
+func (securityBackend) get(account string) (string, bool, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("security", "find-generic-password", "-s", keyringService, "-a", account, "-w")
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == securityItemNotFound {
+			return "", false, nil
+		}
+		return "", false, commandError(err, &stderr)
+	}
+	return stdout.String(), true, nil
+}
+
+func (securityBackend) delete(account string) error {
+	cmd := exec.Command("security", "delete-generic-password", "-s", keyringService, "-a", account)
+	err := runKeyringCommand(cmd)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == securityItemNotFound {
+		return nil // Nothing to delete
+	}
+	return err
+}
+
+// runKeyringCommand runs a keyring tool, including its stderr in any error
+func runKeyringCommand(cmd *exec.Cmd) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return commandError(err, &stderr)
+	}
+	return nil
+}
+
+// commandError wraps err with the tool's error output, if any
+func commandError(err error, stderr *bytes.Buffer) error {
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return fmt.Errorf("%w: %s", err, msg)
+	}
+	return err
+}
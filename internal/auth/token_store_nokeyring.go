@@ -0,0 +1,10 @@
+//go:build !keyring
+
+package auth
+
+import "errors"
+
+// newKeyringTokenStore reports that keyring support was not compiled in
+func newKeyringTokenStore() (TokenStore, error) {
+	return nil, errors.New("keyring token store not available: rebuild with -tags keyring")
+}
@@ -0,0 +1,15 @@
+//go:build !keyring
+
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewTokenStore_KeyringNotBuilt(t *testing.T) {
+	_, err := NewTokenStore(TokenStoreKeyring)
+	if err == nil || !strings.Contains(err.Error(), "-tags keyring") {
+		t.Errorf("Expected an error asking for -tags keyring, got %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/spandigital/mcp-server-dump/internal/configdir"
+)
+
+// testTokenCache returns a cached token for resourceURI
+func testTokenCache(resourceURI string) *TokenCache {
+	return FromOAuth2Token(&oauth2.Token{
+		AccessToken:  "access-" + resourceURI,
+		RefreshToken: "refresh-" + resourceURI,
+		TokenType:    "Bearer",
+		Expiry:       time.Now().Add(time.Hour).Truncate(time.Second),
+	}, resourceURI, []string{"mcp:tools"})
+}
+
+func TestEncryptedTokenStore_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		val  string
+	}{
+		{"key", TokenKeyEnv, base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))},
+		{"passphrase", TokenPassphraseEnv, "correct horse battery staple"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(configdir.HomeEnv, t.TempDir())
+			t.Setenv(TokenKeyEnv, "")
+			t.Setenv(TokenPassphraseEnv, "")
+			t.Setenv(tt.env, tt.val)
+
+			store, err := NewTokenStore(TokenStoreEncrypted)
+			if err != nil {
+				t.Fatalf("NewTokenStore failed: %v", err)
+			}
+			want := testTokenCache("https://mcp.example.com/mcp")
+			if err := store.Save(want); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+
+			// The token must not be readable on disk
+			dir, _ := tokenCacheDir()
+			data, err := os.ReadFile(filepath.Join(dir, tokenCacheName(want.ResourceURI)+".enc"))
+			if err != nil {
+				t.Fatalf("Expected an encrypted token file: %v", err)
+			}
+			if bytes.Contains(data, []byte(want.AccessToken)) {
+				t.Error("Encrypted token file contains the access token in plaintext")
+			}
+
+			got, err := store.Load(want.ResourceURI)
+			if err != nil || got == nil {
+				t.Fatalf("Load failed: %+v, %v", got, err)
+			}
+			if got.AccessToken != want.AccessToken || got.RefreshToken != want.RefreshToken || !got.Expiry.Equal(want.Expiry) {
+				t.Errorf("Load returned %+v, want %+v", got, want)
+			}
+
+			servers, err := store.List()
+			if err != nil || len(servers) != 1 || servers[0] != want.ResourceURI {
+				t.Errorf("List returned %v, %v", servers, err)
+			}
+
+			if err := store.Delete(want.ResourceURI); err != nil {
+				t.Fatalf("Delete failed: %v", err)
+			}
+			if got, err := store.Load(want.ResourceURI); got != nil || err != nil {
+				t.Errorf("Expected no token after Delete, got %+v, %v", got, err)
+			}
+		})
+	}
+}
+
+func TestEncryptedTokenStore_WrongSecret(t *testing.T) {
+	t.Setenv(configdir.HomeEnv, t.TempDir())
+	t.Setenv(TokenKeyEnv, "")
+	t.Setenv(TokenPassphraseEnv, "first passphrase")
+
+	store, err := NewTokenStore(TokenStoreEncrypted)
+	if err != nil {
+		t.Fatalf("NewTokenStore failed: %v", err)
+	}
+	if err := store.Save(testTokenCache("https://mcp.example.com/mcp")); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	t.Setenv(TokenPassphraseEnv, "second passphrase")
+	other, err := NewTokenStore(TokenStoreEncrypted)
+	if err != nil {
+		t.Fatalf("NewTokenStore failed: %v", err)
+	}
+	if _, err := other.Load("https://mcp.example.com/mcp"); err == nil {
+		t.Error("Expected loading with the wrong passphrase to fail")
+	}
+
+	t.Setenv(TokenPassphraseEnv, "")
+	if _, err := NewTokenStore(TokenStoreEncrypted); err == nil {
+		t.Error("Expected an error without a key or passphrase")
+	}
+	t.Setenv(TokenKeyEnv, "too-short")
+	if _, err := NewTokenStore(TokenStoreEncrypted); err == nil {
+		t.Error("Expected an error for a key that is not 32 bytes")
+	}
+}
+
+func TestNewTokenStore_MigratesFileTokens(t *testing.T) {
+	t.Setenv(configdir.HomeEnv, t.TempDir())
+	t.Setenv(TokenKeyEnv, base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32)))
+
+	servers := []string{"https://a.example.com/mcp", "https://b.example.com/mcp"}
+	for _, server := range servers {
+		if err := (fileTokenStore{}).Save(testTokenCache(server)); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	store, err := NewTokenStore(TokenStoreEncrypted)
+	if err != nil {
+		t.Fatalf("NewTokenStore failed: %v", err)
+	}
+
+	if remaining, _ := ListCachedServers(); len(remaining) != 0 {
+		t.Errorf("Expected the plaintext tokens to be removed, got %v", remaining)
+	}
+	for _, server := range servers {
+		if cached, err := store.Load(server); err != nil || cached == nil || cached.AccessToken != "access-"+server {
+			t.Errorf("Expected %s to be migrated, got %+v, %v", server, cached, err)
+		}
+	}
+
+	// Clearing the file store leaves the encrypted tokens alone
+	if err := ClearAllTokens(); err != nil {
+		t.Fatalf("ClearAllTokens failed: %v", err)
+	}
+	if migrated, _ := store.List(); len(migrated) != len(servers) {
+		t.Errorf("Expected %d encrypted tokens after clearing the file store, got %v", len(servers), migrated)
+	}
+}
+
+func TestNewTokenStore_Unknown(t *testing.T) {
+	if _, err := NewTokenStore("vault"); err == nil {
+		t.Error("Expected an error for an unknown token store")
+	}
+}
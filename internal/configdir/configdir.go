@@ -0,0 +1,68 @@
+// Package configdir locates the directory holding mcp-server-dump state (cached tokens,
+// client registrations and detected transports) and guards the files in it against
+// concurrent updates from several processes.
+package configdir
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// HomeEnv overrides the configuration directory, like --config-dir
+const HomeEnv = "MCP_SERVER_DUMP_HOME"
+
+// appName is the directory name under XDG_CONFIG_HOME
+const appName = "mcp-server-dump"
+
+var (
+	mu       sync.RWMutex
+	override string
+)
+
+// Set makes Dir return dir, taking precedence over the environment. An empty dir restores
+// the default lookup.
+func Set(dir string) {
+	mu.Lock()
+	defer mu.Unlock()
+	override = dir
+}
+
+// Dir returns the configuration directory, in order of precedence:
+//  1. the directory given to Set (--config-dir)
+//  2. $MCP_SERVER_DUMP_HOME
+//  3. $XDG_CONFIG_HOME/mcp-server-dump, if XDG_CONFIG_HOME is an absolute path
+//  4. ~/.config/mcp-server-dump
+func Dir() (string, error) {
+	mu.RLock()
+	dir := override
+	mu.RUnlock()
+	if dir != "" {
+		return filepath.Abs(dir)
+	}
+
+	if dir := os.Getenv(HomeEnv); dir != "" {
+		return filepath.Abs(dir)
+	}
+
+	// The XDG Base Directory specification says relative paths are invalid and should be ignored
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" && filepath.IsAbs(dir) {
+		return filepath.Join(dir, appName), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", appName), nil
+}
+
+// Sub returns a subdirectory of the configuration directory, such as "tokens"
+func Sub(name string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
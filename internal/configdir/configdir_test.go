@@ -0,0 +1,109 @@
+package configdir
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDir(t *testing.T) {
+	home := t.TempDir()
+	xdg := filepath.Join(home, "xdg")
+	custom := filepath.Join(home, "custom")
+	flag := filepath.Join(home, "flag")
+
+	tests := []struct {
+		name    string
+		set     string
+		homeEnv string
+		xdg     string
+		want    string
+	}{
+		{name: "default", want: filepath.Join(home, ".config", "mcp-server-dump")},
+		{name: "xdg_config_home", xdg: xdg, want: filepath.Join(xdg, "mcp-server-dump")},
+		{name: "relative_xdg_config_home_ignored", xdg: "relative", want: filepath.Join(home, ".config", "mcp-server-dump")},
+		{name: "home_env_over_xdg", homeEnv: custom, xdg: xdg, want: custom},
+		{name: "set_over_env", set: flag, homeEnv: custom, xdg: xdg, want: flag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", home)
+			t.Setenv("USERPROFILE", home) // os.UserHomeDir on Windows
+			t.Setenv(HomeEnv, tt.homeEnv)
+			t.Setenv("XDG_CONFIG_HOME", tt.xdg)
+			Set(tt.set)
+			t.Cleanup(func() { Set("") })
+
+			got, err := Dir()
+			if err != nil {
+				t.Fatalf("Dir failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Dir() = %q, want %q", got, tt.want)
+			}
+
+			sub, err := Sub("tokens")
+			if err != nil || sub != filepath.Join(tt.want, "tokens") {
+				t.Errorf("Sub(tokens) = %q, %v", sub, err)
+			}
+		})
+	}
+}
+
+func TestLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens", "abc")
+
+	unlock, err := Lock(path)
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	// A second lock on the same file waits for the first to be released
+	acquired := make(chan func())
+	go func() {
+		second, lockErr := Lock(path)
+		if lockErr != nil {
+			t.Errorf("Second Lock failed: %v", lockErr)
+			close(acquired)
+			return
+		}
+		acquired <- second
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("Expected the second lock to wait while the first is held")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case second := <-acquired:
+		if second != nil {
+			second()
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the second lock to be acquired after the first was released")
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token.json")
+
+	for _, content := range []string{"first", "second"} {
+		if err := WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != content {
+			t.Errorf("Expected %q, got %q, %v", content, data, err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Errorf("Expected only the written file to remain, got %v, %v", entries, err)
+	}
+}
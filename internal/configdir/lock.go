@@ -0,0 +1,62 @@
+package configdir
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Lock takes an exclusive lock on path+".lock", waiting for any other process holding it,
+// and returns the function that releases it. It serializes read-modify-write sequences on a
+// cache file, such as refreshing a token, between processes sharing the directory. The lock
+// file is left in place, as removing it would let a waiting process lock a file no one else sees.
+func Lock(path string) (unlock func(), err error) {
+	lockPath := path + ".lock"
+	if mkdirErr := os.MkdirAll(filepath.Dir(lockPath), 0o700); mkdirErr != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", mkdirErr)
+	}
+
+	// #nosec G304 - lockPath is derived from a cache file path built by the caller
+	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	if err := lockFile(file); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", lockPath, err)
+	}
+
+	return func() {
+		_ = unlockFile(file)
+		_ = file.Close()
+	}, nil
+}
+
+// WriteFile writes data to path through a temporary file in the same directory and renames it
+// into place, so a concurrent reader sees either the old or the new content, never a partial file
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer func() {
+		_ = os.Remove(tmpPath) // No-op once renamed
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
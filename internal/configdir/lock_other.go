@@ -0,0 +1,15 @@
+//go:build !unix && !windows
+
+package configdir
+
+import "os"
+
+// lockFile is a no-op on platforms without file locking
+func lockFile(*os.File) error {
+	return nil
+}
+
+// unlockFile is a no-op on platforms without file locking
+func unlockFile(*os.File) error {
+	return nil
+}
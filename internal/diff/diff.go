@@ -0,0 +1,248 @@
+// Package diff compares two server dumps and classifies the differences
+// as breaking or non-breaking for clients of the server.
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spandigital/mcp-server-dump/internal/model"
+)
+
+// Kinds of server items that can change
+const (
+	KindTool             = "tool"
+	KindResource         = "resource"
+	KindResourceTemplate = "resourceTemplate"
+	KindPrompt           = "prompt"
+)
+
+// Action describes how an item changed between the baseline and the current dump
+type Action string
+
+// Change actions
+const (
+	Added   Action = "added"
+	Removed Action = "removed"
+	Changed Action = "changed"
+)
+
+// Change is a single difference between two dumps. Path locates field-level
+// changes within the item, e.g. "inputSchema.properties.path".
+type Change struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Action   Action `json:"action"`
+	Path     string `json:"path,omitempty"`
+	Detail   string `json:"detail"`
+	Breaking bool   `json:"breaking"`
+}
+
+// ServerRef identifies one side of a comparison
+type ServerRef struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Report holds every change found between a baseline and a current dump
+type Report struct {
+	Baseline ServerRef `json:"baseline"`
+	Current  ServerRef `json:"current"`
+	Changes  []Change  `json:"changes"`
+}
+
+// HasBreaking reports whether any change is breaking
+func (r *Report) HasBreaking() bool {
+	return slices.ContainsFunc(r.Changes, func(c Change) bool { return c.Breaking })
+}
+
+// BreakingCount returns the number of breaking changes
+func (r *Report) BreakingCount() int {
+	count := 0
+	for _, c := range r.Changes {
+		if c.Breaking {
+			count++
+		}
+	}
+	return count
+}
+
+// Compare reports the changes from baseline to current
+func Compare(baseline, current *model.ServerInfo) *Report {
+	report := &Report{
+		Baseline: ServerRef{Name: baseline.Name, Version: baseline.Version},
+		Current:  ServerRef{Name: current.Name, Version: current.Version},
+		Changes:  []Change{},
+	}
+
+	report.Changes = append(report.Changes, compareTools(baseline.Tools, current.Tools)...)
+	report.Changes = append(report.Changes, compareResources(baseline.Resources, current.Resources)...)
+	report.Changes = append(report.Changes, compareResourceTemplates(baseline.ResourceTemplates, current.ResourceTemplates)...)
+	report.Changes = append(report.Changes, comparePrompts(baseline.Prompts, current.Prompts)...)
+
+	return report
+}
+
+// matchItems pairs items by key and calls changed for every key present on both sides.
+// Removed and added items are reported first, in sorted key order, so output is stable.
+func matchItems[T any](kind string, baseline, current []T, key func(T) string, changed func(before, after T) []Change) []Change {
+	oldByKey := make(map[string]T, len(baseline))
+	for _, item := range baseline {
+		oldByKey[key(item)] = item
+	}
+	newByKey := make(map[string]T, len(current))
+	for _, item := range current {
+		newByKey[key(item)] = item
+	}
+
+	var changes []Change
+	for _, name := range sortedKeys(oldByKey) {
+		if _, ok := newByKey[name]; !ok {
+			changes = append(changes, Change{Kind: kind, Name: name, Action: Removed, Detail: kind + " removed", Breaking: true})
+		}
+	}
+	for _, name := range sortedKeys(newByKey) {
+		if _, ok := oldByKey[name]; !ok {
+			changes = append(changes, Change{Kind: kind, Name: name, Action: Added, Detail: kind + " added"})
+		}
+	}
+	for _, name := range sortedKeys(oldByKey) {
+		if newItem, ok := newByKey[name]; ok {
+			changes = append(changes, changed(oldByKey[name], newItem)...)
+		}
+	}
+	return changes
+}
+
+// compareTools compares tools by name, including their input and output schemas
+func compareTools(baseline, current []model.Tool) []Change {
+	return matchItems(KindTool, baseline, current, func(t model.Tool) string { return t.Name }, func(before, after model.Tool) []Change {
+		var changes []Change
+		add := func(path, detail string, breaking bool) {
+			changes = append(changes, Change{Kind: KindTool, Name: before.Name, Action: Changed, Path: path, Detail: detail, Breaking: breaking})
+		}
+
+		if before.Title != after.Title {
+			add("title", fmt.Sprintf("title changed from %q to %q", before.Title, after.Title), false)
+		}
+		if before.Description != after.Description {
+			add("description", "description changed", false)
+		}
+		if oldBadges, newBadges := strings.Join(before.Badges(), ", "), strings.Join(after.Badges(), ", "); oldBadges != newBadges {
+			add("annotations", fmt.Sprintf("annotations changed from [%s] to [%s]", oldBadges, newBadges), false)
+		}
+
+		for _, sc := range compareSchemas("inputSchema", before.InputSchema, after.InputSchema, inputDirection) {
+			add(sc.path, sc.detail, sc.breaking)
+		}
+
+		switch {
+		case before.OutputSchema == nil && after.OutputSchema != nil:
+			add("outputSchema", "output schema added", false)
+		case before.OutputSchema != nil && after.OutputSchema == nil:
+			add("outputSchema", "output schema removed; structured results are no longer guaranteed", true)
+		case before.OutputSchema != nil:
+			for _, sc := range compareSchemas("outputSchema", before.OutputSchema, after.OutputSchema, outputDirection) {
+				add(sc.path, sc.detail, sc.breaking)
+			}
+		}
+		return changes
+	})
+}
+
+// compareResources compares resources by URI
+func compareResources(baseline, current []model.Resource) []Change {
+	return matchItems(KindResource, baseline, current, func(r model.Resource) string { return r.URI }, func(before, after model.Resource) []Change {
+		return compareDescriptive(KindResource, before.URI, descriptive{before.Name, before.Description, before.MimeType}, descriptive{after.Name, after.Description, after.MimeType})
+	})
+}
+
+// compareResourceTemplates compares resource templates by URI template
+func compareResourceTemplates(baseline, current []model.ResourceTemplate) []Change {
+	return matchItems(KindResourceTemplate, baseline, current, func(r model.ResourceTemplate) string { return r.URITemplate }, func(before, after model.ResourceTemplate) []Change {
+		return compareDescriptive(KindResourceTemplate, before.URITemplate, descriptive{before.Name, before.Description, before.MimeType}, descriptive{after.Name, after.Description, after.MimeType})
+	})
+}
+
+// descriptive holds the fields shared by resources and resource templates
+type descriptive struct {
+	name, description, mimeType string
+}
+
+// compareDescriptive compares the name, description and MIME type of a resource or template.
+// A MIME type change is breaking because clients may parse the contents by type.
+func compareDescriptive(kind, key string, before, after descriptive) []Change {
+	var changes []Change
+	if before.name != after.name {
+		changes = append(changes, Change{Kind: kind, Name: key, Action: Changed, Path: "name", Detail: fmt.Sprintf("name changed from %q to %q", before.name, after.name)})
+	}
+	if before.description != after.description {
+		changes = append(changes, Change{Kind: kind, Name: key, Action: Changed, Path: "description", Detail: "description changed"})
+	}
+	if before.mimeType != after.mimeType {
+		changes = append(changes, Change{Kind: kind, Name: key, Action: Changed, Path: "mimeType", Detail: fmt.Sprintf("MIME type changed from %q to %q", before.mimeType, after.mimeType), Breaking: true})
+	}
+	return changes
+}
+
+// comparePrompts compares prompts by name, including their arguments
+func comparePrompts(baseline, current []model.Prompt) []Change {
+	return matchItems(KindPrompt, baseline, current, func(p model.Prompt) string { return p.Name }, func(before, after model.Prompt) []Change {
+		var changes []Change
+		add := func(path, detail string, breaking bool) {
+			changes = append(changes, Change{Kind: KindPrompt, Name: before.Name, Action: Changed, Path: path, Detail: detail, Breaking: breaking})
+		}
+
+		if before.Description != after.Description {
+			add("description", "description changed", false)
+		}
+
+		oldArgs := make(map[string]model.PromptArgument)
+		for _, arg := range before.ParsedArguments() {
+			oldArgs[arg.Name] = arg
+		}
+		newArgs := make(map[string]model.PromptArgument)
+		for _, arg := range after.ParsedArguments() {
+			newArgs[arg.Name] = arg
+		}
+
+		for _, name := range sortedKeys(oldArgs) {
+			oldArg := oldArgs[name]
+			newArg, ok := newArgs[name]
+			path := "arguments." + name
+			switch {
+			case !ok:
+				add(path, "argument removed", true)
+			case !oldArg.Required && newArg.Required:
+				add(path, "argument is now required", true)
+			case oldArg.Required && !newArg.Required:
+				add(path, "argument is no longer required", false)
+			}
+			if ok && oldArg.Description != newArg.Description {
+				add(path, "argument description changed", false)
+			}
+		}
+		for _, name := range sortedKeys(newArgs) {
+			if _, ok := oldArgs[name]; ok {
+				continue
+			}
+			if newArgs[name].Required {
+				add("arguments."+name, "required argument added", true)
+			} else {
+				add("arguments."+name, "optional argument added", false)
+			}
+		}
+		return changes
+	})
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
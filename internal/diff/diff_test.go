@@ -0,0 +1,380 @@
+package diff
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/spandigital/mcp-server-dump/internal/model"
+)
+
+// findChange returns the first change matching kind, name and path, or nil
+func findChange(report *Report, kind, name, path string) *Change {
+	for i := range report.Changes {
+		c := &report.Changes[i]
+		if c.Kind == kind && c.Name == name && c.Path == path {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestCompare_Items(t *testing.T) {
+	baseline := &model.ServerInfo{
+		Name:    "test-server",
+		Version: "1.0.0",
+		Tools: []model.Tool{
+			{Name: "kept", Description: "Old description"},
+			{Name: "dropped"},
+		},
+		Resources: []model.Resource{
+			{URI: "file:///readme.md", Name: "readme", MimeType: "text/markdown"},
+			{URI: "file:///old.txt", Name: "old"},
+		},
+		ResourceTemplates: []model.ResourceTemplate{
+			{URITemplate: "file:///{path}", Name: "files"},
+		},
+		Prompts: []model.Prompt{
+			{Name: "review"},
+		},
+	}
+	current := &model.ServerInfo{
+		Name:    "test-server",
+		Version: "2.0.0",
+		Tools: []model.Tool{
+			{Name: "kept", Description: "New description"},
+			{Name: "added"},
+		},
+		Resources: []model.Resource{
+			{URI: "file:///readme.md", Name: "readme", MimeType: "text/plain"},
+		},
+		Prompts: []model.Prompt{
+			{Name: "review"},
+			{Name: "summarize"},
+		},
+	}
+
+	report := Compare(baseline, current)
+
+	tests := []struct {
+		kind, name, path string
+		action           Action
+		breaking         bool
+	}{
+		{KindTool, "dropped", "", Removed, true},
+		{KindTool, "added", "", Added, false},
+		{KindTool, "kept", "description", Changed, false},
+		{KindResource, "file:///old.txt", "", Removed, true},
+		{KindResource, "file:///readme.md", "mimeType", Changed, true},
+		{KindResourceTemplate, "file:///{path}", "", Removed, true},
+		{KindPrompt, "summarize", "", Added, false},
+	}
+	for _, tt := range tests {
+		c := findChange(report, tt.kind, tt.name, tt.path)
+		if c == nil {
+			t.Errorf("Expected %s %q change at %q, got %+v", tt.kind, tt.name, tt.path, report.Changes)
+			continue
+		}
+		if c.Action != tt.action || c.Breaking != tt.breaking {
+			t.Errorf("%s %q %q: expected action=%s breaking=%v, got action=%s breaking=%v", tt.kind, tt.name, tt.path, tt.action, tt.breaking, c.Action, c.Breaking)
+		}
+	}
+
+	if len(report.Changes) != len(tests) {
+		t.Errorf("Expected %d changes, got %d: %+v", len(tests), len(report.Changes), report.Changes)
+	}
+	if !report.HasBreaking() || report.BreakingCount() != 4 {
+		t.Errorf("Expected 4 breaking changes, got %d", report.BreakingCount())
+	}
+}
+
+func TestCompare_NoChanges(t *testing.T) {
+	info := &model.ServerInfo{
+		Name:  "test-server",
+		Tools: []model.Tool{{Name: "echo", InputSchema: map[string]any{"type": "object"}}},
+	}
+
+	report := Compare(info, info)
+	if len(report.Changes) != 0 || report.HasBreaking() {
+		t.Errorf("Expected no changes, got %+v", report.Changes)
+	}
+}
+
+func TestCompareSchemas(t *testing.T) {
+	object := func(props map[string]any, required ...any) map[string]any {
+		schema := map[string]any{"type": "object", "properties": props}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	}
+	enum := func(values ...any) map[string]any {
+		return map[string]any{"type": "string", "enum": values}
+	}
+
+	tests := []struct {
+		name         string
+		before       any
+		after        any
+		dir          direction
+		wantPath     string
+		wantBreaking bool
+	}{
+		{
+			name:         "input_required_property_added",
+			before:       object(map[string]any{}),
+			after:        object(map[string]any{"path": map[string]any{"type": "string"}}, "path"),
+			dir:          inputDirection,
+			wantPath:     "inputSchema.properties.path",
+			wantBreaking: true,
+		},
+		{
+			name:         "input_optional_property_added",
+			before:       object(map[string]any{}),
+			after:        object(map[string]any{"limit": map[string]any{"type": "integer"}}),
+			dir:          inputDirection,
+			wantPath:     "inputSchema.properties.limit",
+			wantBreaking: false,
+		},
+		{
+			name:         "input_property_removed",
+			before:       object(map[string]any{"limit": map[string]any{"type": "integer"}}),
+			after:        object(map[string]any{}),
+			dir:          inputDirection,
+			wantPath:     "inputSchema.properties.limit",
+			wantBreaking: true,
+		},
+		{
+			name:         "input_property_becomes_required",
+			before:       object(map[string]any{"limit": map[string]any{"type": "integer"}}),
+			after:        object(map[string]any{"limit": map[string]any{"type": "integer"}}, "limit"),
+			dir:          inputDirection,
+			wantPath:     "inputSchema.properties.limit",
+			wantBreaking: true,
+		},
+		{
+			name:         "input_property_becomes_optional",
+			before:       object(map[string]any{"limit": map[string]any{"type": "integer"}}, "limit"),
+			after:        object(map[string]any{"limit": map[string]any{"type": "integer"}}),
+			dir:          inputDirection,
+			wantPath:     "inputSchema.properties.limit",
+			wantBreaking: false,
+		},
+		{
+			name:         "input_enum_narrowed",
+			before:       object(map[string]any{"mode": enum("fast", "safe")}),
+			after:        object(map[string]any{"mode": enum("safe")}),
+			dir:          inputDirection,
+			wantPath:     "inputSchema.properties.mode",
+			wantBreaking: true,
+		},
+		{
+			name:         "input_enum_widened",
+			before:       object(map[string]any{"mode": enum("safe")}),
+			after:        object(map[string]any{"mode": enum("fast", "safe")}),
+			dir:          inputDirection,
+			wantPath:     "inputSchema.properties.mode",
+			wantBreaking: false,
+		},
+		{
+			name:         "output_enum_widened",
+			before:       object(map[string]any{"status": enum("ok")}),
+			after:        object(map[string]any{"status": enum("ok", "pending")}),
+			dir:          outputDirection,
+			wantPath:     "outputSchema.properties.status",
+			wantBreaking: true,
+		},
+		{
+			name:         "output_property_no_longer_required",
+			before:       object(map[string]any{"id": map[string]any{"type": "string"}}, "id"),
+			after:        object(map[string]any{"id": map[string]any{"type": "string"}}),
+			dir:          outputDirection,
+			wantPath:     "outputSchema.properties.id",
+			wantBreaking: true,
+		},
+		{
+			name:         "output_property_added",
+			before:       object(map[string]any{}),
+			after:        object(map[string]any{"extra": map[string]any{"type": "string"}}, "extra"),
+			dir:          outputDirection,
+			wantPath:     "outputSchema.properties.extra",
+			wantBreaking: false,
+		},
+		{
+			name:         "input_type_changed",
+			before:       object(map[string]any{"count": map[string]any{"type": []any{"integer", "string"}}}),
+			after:        object(map[string]any{"count": map[string]any{"type": "integer"}}),
+			dir:          inputDirection,
+			wantPath:     "inputSchema.properties.count",
+			wantBreaking: true,
+		},
+		{
+			name:         "input_type_widened",
+			before:       object(map[string]any{"count": map[string]any{"type": "integer"}}),
+			after:        object(map[string]any{"count": map[string]any{"type": []any{"integer", "string"}}}),
+			dir:          inputDirection,
+			wantPath:     "inputSchema.properties.count",
+			wantBreaking: false,
+		},
+		{
+			name: "nested_array_items",
+			before: object(map[string]any{"tags": map[string]any{
+				"type":  "array",
+				"items": object(map[string]any{"name": map[string]any{"type": "string"}}),
+			}}),
+			after: object(map[string]any{"tags": map[string]any{
+				"type":  "array",
+				"items": object(map[string]any{}),
+			}}),
+			dir:          inputDirection,
+			wantPath:     "inputSchema.properties.tags.items.properties.name",
+			wantBreaking: true,
+		},
+		{
+			// Hand-edited dumps may hold any JSON value where a string is expected
+			name:         "malformed_description",
+			before:       map[string]any{"type": "object", "description": map[string]any{"text": "old"}},
+			after:        map[string]any{"type": "object", "description": []any{"new"}},
+			dir:          inputDirection,
+			wantPath:     "inputSchema",
+			wantBreaking: false,
+		},
+		{
+			name:         "schema_not_an_object",
+			before:       object(map[string]any{}),
+			after:        "not a schema",
+			dir:          inputDirection,
+			wantPath:     "inputSchema",
+			wantBreaking: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := "inputSchema"
+			if tt.dir == outputDirection {
+				root = "outputSchema"
+			}
+			changes := compareSchemas(root, tt.before, tt.after, tt.dir)
+			if len(changes) != 1 {
+				t.Fatalf("Expected exactly one change, got %+v", changes)
+			}
+			if changes[0].path != tt.wantPath {
+				t.Errorf("Expected path %q, got %q", tt.wantPath, changes[0].path)
+			}
+			if changes[0].breaking != tt.wantBreaking {
+				t.Errorf("Expected breaking=%v, got %v (%s)", tt.wantBreaking, changes[0].breaking, changes[0].detail)
+			}
+		})
+	}
+}
+
+func TestCompare_PromptArguments(t *testing.T) {
+	baseline := &model.ServerInfo{Prompts: []model.Prompt{{
+		Name: "review",
+		Arguments: []any{
+			map[string]any{"name": "code", "required": true},
+			map[string]any{"name": "style"},
+			map[string]any{"name": "legacy"},
+		},
+	}}}
+	current := &model.ServerInfo{Prompts: []model.Prompt{{
+		Name: "review",
+		Arguments: []any{
+			map[string]any{"name": "code"},
+			map[string]any{"name": "style", "required": true},
+			map[string]any{"name": "language", "required": true},
+			map[string]any{"name": "focus"},
+		},
+	}}}
+
+	report := Compare(baseline, current)
+
+	want := map[string]bool{
+		"arguments.code":     false,
+		"arguments.style":    true,
+		"arguments.legacy":   true,
+		"arguments.language": true,
+		"arguments.focus":    false,
+	}
+	for path, breaking := range want {
+		c := findChange(report, KindPrompt, "review", path)
+		if c == nil {
+			t.Errorf("Expected change at %s, got %+v", path, report.Changes)
+			continue
+		}
+		if c.Breaking != breaking {
+			t.Errorf("%s: expected breaking=%v, got %v (%s)", path, breaking, c.Breaking, c.Detail)
+		}
+	}
+}
+
+func TestCompare_OutputSchemaRemoved(t *testing.T) {
+	schema := map[string]any{"type": "object"}
+	baseline := &model.ServerInfo{Tools: []model.Tool{{Name: "fetch", OutputSchema: schema}}}
+	current := &model.ServerInfo{Tools: []model.Tool{{Name: "fetch"}}}
+
+	c := findChange(Compare(baseline, current), KindTool, "fetch", "outputSchema")
+	if c == nil || !c.Breaking {
+		t.Errorf("Expected removing the output schema to be breaking, got %+v", c)
+	}
+
+	c = findChange(Compare(current, baseline), KindTool, "fetch", "outputSchema")
+	if c == nil || c.Breaking {
+		t.Errorf("Expected adding an output schema to be non-breaking, got %+v", c)
+	}
+}
+
+func TestFormatMarkdown(t *testing.T) {
+	report := &Report{
+		Baseline: ServerRef{Name: "test-server", Version: "1.0.0"},
+		Current:  ServerRef{Name: "test-server", Version: "2.0.0"},
+		Changes: []Change{
+			{Kind: KindTool, Name: "search", Action: Changed, Path: "inputSchema.properties.query", Detail: "required property added", Breaking: true},
+			{Kind: KindTool, Name: "echo", Action: Added, Detail: "tool added"},
+		},
+	}
+
+	output := string(FormatMarkdown(report))
+
+	for _, want := range []string{
+		"# API Changes: test-server",
+		"test-server 2.0.0 against baseline test-server 1.0.0",
+		"**2 change(s), 1 breaking**",
+		"## Breaking Changes",
+		"| tool | `search` | changed | `inputSchema.properties.query` | required property added |",
+		"## Non-Breaking Changes",
+		"| tool | `echo` | added | - | tool added |",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected markdown to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "## Breaking Changes") > strings.Index(output, "## Non-Breaking Changes") {
+		t.Error("Expected breaking changes to be listed first")
+	}
+
+	empty := string(FormatMarkdown(&Report{Changes: []Change{}}))
+	if !strings.Contains(empty, "No changes detected.") {
+		t.Errorf("Expected empty report notice, got:\n%s", empty)
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	report := &Report{
+		Changes: []Change{{Kind: KindPrompt, Name: "review", Action: Removed, Detail: "prompt removed", Breaking: true}},
+	}
+
+	data, err := FormatJSON(report)
+	if err != nil {
+		t.Fatalf("FormatJSON failed: %v", err)
+	}
+
+	var decoded Report
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to decode report: %v", err)
+	}
+	if len(decoded.Changes) != 1 || !decoded.Changes[0].Breaking || decoded.Changes[0].Action != Removed {
+		t.Errorf("Unexpected decoded report: %+v", decoded)
+	}
+}
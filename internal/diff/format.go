@@ -0,0 +1,79 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// FormatJSON formats a report as indented JSON
+func FormatJSON(report *Report) ([]byte, error) {
+	return json.MarshalIndent(report, "", "  ")
+}
+
+// FormatMarkdown formats a report as markdown, listing breaking changes first
+func FormatMarkdown(report *Report) []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# API Changes: %s\n\n", report.Current.Name)
+	fmt.Fprintf(&b, "Comparing %s against baseline %s.\n\n", describeRef(report.Current), describeRef(report.Baseline))
+
+	if len(report.Changes) == 0 {
+		b.WriteString("No changes detected.\n")
+		return []byte(b.String())
+	}
+
+	breaking := report.BreakingCount()
+	fmt.Fprintf(&b, "**%d change(s), %d breaking**\n\n", len(report.Changes), breaking)
+
+	var breakingChanges, otherChanges []Change
+	for _, c := range report.Changes {
+		if c.Breaking {
+			breakingChanges = append(breakingChanges, c)
+		} else {
+			otherChanges = append(otherChanges, c)
+		}
+	}
+
+	writeChangeTable(&b, "Breaking Changes", breakingChanges)
+	writeChangeTable(&b, "Non-Breaking Changes", otherChanges)
+
+	return []byte(strings.TrimRight(b.String(), "\n") + "\n")
+}
+
+// writeChangeTable writes a section containing a table of changes, or nothing if there are none
+func writeChangeTable(b *strings.Builder, title string, changes []Change) {
+	if len(changes) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "## %s\n\n", title)
+	b.WriteString("| Kind | Name | Action | Path | Detail |\n")
+	b.WriteString("|------|------|--------|------|--------|\n")
+	for _, c := range changes {
+		path := "-"
+		if c.Path != "" {
+			path = "`" + c.Path + "`"
+		}
+		fmt.Fprintf(b, "| %s | `%s` | %s | %s | %s |\n", c.Kind, escapeCell(c.Name), c.Action, escapeCell(path), escapeCell(c.Detail))
+	}
+	b.WriteString("\n")
+}
+
+// describeRef formats a server name and version for display
+func describeRef(ref ServerRef) string {
+	name := ref.Name
+	if name == "" {
+		name = "(unnamed server)"
+	}
+	if ref.Version == "" {
+		return name
+	}
+	return name + " " + ref.Version
+}
+
+// escapeCell escapes characters that would break a markdown table cell
+func escapeCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,239 @@
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/spandigital/mcp-server-dump/internal/model"
+)
+
+// maxSchemaDepth stops the comparison from following deeply nested schemas forever
+const maxSchemaDepth = 32
+
+// direction records which way data flows through a schema. A change that is safe for
+// one direction is usually breaking for the other: narrowing what a tool accepts breaks
+// existing callers, while widening what a tool returns breaks existing consumers.
+type direction int
+
+const (
+	inputDirection direction = iota
+	outputDirection
+)
+
+// schemaChange is a single field-level difference between two schemas
+type schemaChange struct {
+	path     string
+	detail   string
+	breaking bool
+}
+
+// compareSchemas compares two JSON Schemas given in any Go representation.
+// A missing schema is treated as an empty schema that accepts anything.
+// A schema that is not a JSON object, as in a hand-edited dump, is reported as a change
+// rather than compared.
+func compareSchemas(path string, before, after any, dir direction) []schemaChange {
+	oldSchema, oldErr := model.SchemaMap(before)
+	newSchema, newErr := model.SchemaMap(after)
+	if err := errors.Join(oldErr, newErr); err != nil {
+		if reflect.DeepEqual(before, after) {
+			return nil
+		}
+		return []schemaChange{{path: path, detail: fmt.Sprintf("schema changed but could not be compared: %v", err), breaking: true}}
+	}
+
+	var changes []schemaChange
+	compareSchemaNode(path, oldSchema, newSchema, dir, 0, &changes)
+	return changes
+}
+
+// compareSchemaNode compares one level of a schema and recurses into properties and items
+func compareSchemaNode(path string, before, after map[string]any, dir direction, depth int, changes *[]schemaChange) {
+	if depth > maxSchemaDepth {
+		return
+	}
+	add := func(subPath, detail string, breaking bool) {
+		*changes = append(*changes, schemaChange{path: subPath, detail: detail, breaking: breaking})
+	}
+
+	if oldTypes, newTypes := typeSet(before), typeSet(after); !slices.Equal(oldTypes, newTypes) {
+		add(path, fmt.Sprintf("type changed from %s to %s", describeSet(oldTypes), describeSet(newTypes)), isIncompatible(oldTypes, newTypes, dir))
+	}
+
+	if oldEnum, newEnum := enumSet(before), enumSet(after); !slices.Equal(oldEnum, newEnum) {
+		add(path, describeEnumChange(oldEnum, newEnum), isIncompatible(oldEnum, newEnum, dir))
+	}
+
+	// Descriptions are compared deeply, as a malformed dump may hold any JSON value
+	if !reflect.DeepEqual(before["description"], after["description"]) {
+		add(path, "description changed", false)
+	}
+
+	compareProperties(path, before, after, dir, depth, changes)
+
+	oldItems, oldOK := before["items"].(map[string]any)
+	newItems, newOK := after["items"].(map[string]any)
+	if oldOK || newOK {
+		compareSchemaNode(path+".items", oldItems, newItems, dir, depth+1, changes)
+	}
+}
+
+// compareProperties compares the properties and required lists of two object schemas
+func compareProperties(path string, before, after map[string]any, dir direction, depth int, changes *[]schemaChange) {
+	oldProps, _ := before["properties"].(map[string]any)
+	newProps, _ := after["properties"].(map[string]any)
+	oldRequired := stringSet(before["required"])
+	newRequired := stringSet(after["required"])
+
+	add := func(subPath, detail string, breaking bool) {
+		*changes = append(*changes, schemaChange{path: subPath, detail: detail, breaking: breaking})
+	}
+
+	for _, name := range sortedKeys(oldProps) {
+		propPath := path + ".properties." + name
+		newProp, ok := newProps[name]
+		if !ok {
+			add(propPath, "property removed", true)
+			continue
+		}
+
+		wasRequired, isRequired := oldRequired[name], newRequired[name]
+		switch {
+		case !wasRequired && isRequired:
+			add(propPath, "property is now required", dir == inputDirection)
+		case wasRequired && !isRequired:
+			add(propPath, "property is no longer required", dir == outputDirection)
+		}
+
+		oldSchema, _ := oldProps[name].(map[string]any)
+		newSchema, _ := newProp.(map[string]any)
+		compareSchemaNode(propPath, oldSchema, newSchema, dir, depth+1, changes)
+	}
+
+	for _, name := range sortedKeys(newProps) {
+		if _, ok := oldProps[name]; ok {
+			continue
+		}
+		propPath := path + ".properties." + name
+		switch {
+		case dir == inputDirection && newRequired[name]:
+			add(propPath, "required property added", true)
+		case dir == inputDirection:
+			add(propPath, "optional property added", false)
+		default:
+			add(propPath, "property added", false)
+		}
+	}
+}
+
+// isIncompatible decides whether a change to a set of allowed values (types or enum members)
+// breaks clients. An empty set means unconstrained. Inputs break when values are no longer
+// accepted; outputs break when values appear that clients have never seen.
+func isIncompatible(before, after []string, dir direction) bool {
+	if dir == outputDirection {
+		before, after = after, before
+	}
+	// The change is breaking if something allowed before is not allowed after
+	if len(after) == 0 {
+		return false
+	}
+	if len(before) == 0 {
+		return true
+	}
+	for _, value := range before {
+		if !slices.Contains(after, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeEnumChange summarises the values added to and removed from an enum
+func describeEnumChange(before, after []string) string {
+	switch {
+	case len(before) == 0:
+		return "enum added: " + strings.Join(after, ", ")
+	case len(after) == 0:
+		return "enum removed"
+	}
+
+	var added, removed []string
+	for _, value := range after {
+		if !slices.Contains(before, value) {
+			added = append(added, value)
+		}
+	}
+	for _, value := range before {
+		if !slices.Contains(after, value) {
+			removed = append(removed, value)
+		}
+	}
+
+	var parts []string
+	if len(removed) > 0 {
+		parts = append(parts, "removed "+strings.Join(removed, ", "))
+	}
+	if len(added) > 0 {
+		parts = append(parts, "added "+strings.Join(added, ", "))
+	}
+	return "enum values changed: " + strings.Join(parts, "; ")
+}
+
+// describeSet formats a set of types for display
+func describeSet(values []string) string {
+	if len(values) == 0 {
+		return "any"
+	}
+	return strings.Join(values, "|")
+}
+
+// typeSet returns the sorted types a schema allows; an empty result means any type
+func typeSet(schema map[string]any) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []any:
+		var types []string
+		for _, entry := range t {
+			if s, ok := entry.(string); ok {
+				types = append(types, s)
+			}
+		}
+		slices.Sort(types)
+		return types
+	}
+	return nil
+}
+
+// enumSet returns the sorted JSON encodings of a schema's enum values
+func enumSet(schema map[string]any) []string {
+	values, ok := schema["enum"].([]any)
+	if !ok {
+		return nil
+	}
+	encoded := make([]string, 0, len(values))
+	for _, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		encoded = append(encoded, string(data))
+	}
+	slices.Sort(encoded)
+	return encoded
+}
+
+// stringSet converts a JSON array of strings, such as a required list, into a set
+func stringSet(value any) map[string]bool {
+	set := make(map[string]bool)
+	entries, _ := value.([]any)
+	for _, entry := range entries {
+		if s, ok := entry.(string); ok {
+			set[s] = true
+		}
+	}
+	return set
+}
@@ -41,6 +41,16 @@ func GenerateFrontmatter(info *model.ServerInfo, format string, customFields map
 		if info.Version != "" {
 			frontmatter["version"] = info.Version
 		}
+		if info.Transport != "" {
+			frontmatter["transport"] = info.Transport
+		}
+		if len(info.CollectionErrors) > 0 {
+			sections := make([]string, 0, len(info.CollectionErrors))
+			for _, collectionErr := range info.CollectionErrors {
+				sections = append(sections, collectionErr.Section)
+			}
+			frontmatter["collection_errors"] = sections
+		}
 
 		// Capabilities
 		frontmatter["capabilities"] = map[string]bool{
@@ -52,6 +62,9 @@ func GenerateFrontmatter(info *model.ServerInfo, format string, customFields map
 		// Counts
 		frontmatter["tools_count"] = len(info.Tools)
 		frontmatter["resources_count"] = len(info.Resources)
+		if len(info.ResourceTemplates) > 0 {
+			frontmatter["resource_templates_count"] = len(info.ResourceTemplates)
+		}
 		frontmatter["prompts_count"] = len(info.Prompts)
 	}
 
@@ -147,7 +147,7 @@ func FormatHugo(info *model.ServerInfo, outputDir string, includeFrontmatter boo
 	return generateContentSections(info, contentDir, includeFrontmatter, frontmatterFormat, customFields, generationTime, customInitialisms, templateFS)
 }
 
-// generateContentSections generates all content sections (tools, resources, prompts) if they exist
+// generateContentSections generates all content sections (tools, resources, resource templates, prompts) if they exist
 func generateContentSections(info *model.ServerInfo, contentDir string, includeFrontmatter bool, frontmatterFormat string, customFields map[string]any, generationTime time.Time, customInitialisms []string, templateFS embed.FS) error {
 	// Generate tools section
 	if len(info.Tools) > 0 {
@@ -163,6 +163,13 @@ func generateContentSections(info *model.ServerInfo, contentDir string, includeF
 		}
 	}
 
+	// Generate resource templates section
+	if len(info.ResourceTemplates) > 0 {
+		if err := generateResourceTemplatesSection(info, contentDir, includeFrontmatter, frontmatterFormat, customFields, generationTime, customInitialisms, templateFS); err != nil {
+			return fmt.Errorf("failed to generate resource templates section: %w", err)
+		}
+	}
+
 	// Generate prompts section
 	if len(info.Prompts) > 0 {
 		if err := generatePromptsSection(info, contentDir, includeFrontmatter, frontmatterFormat, customFields, generationTime, customInitialisms, templateFS); err != nil {
@@ -201,6 +208,9 @@ func generateRootIndex(info *model.ServerInfo, contentDir string, includeFrontma
 	if info.Version != "" {
 		fmt.Fprintf(&content, "**Version:** %s\n\n", info.Version)
 	}
+	if notice := collectionErrorsNotice(info.CollectionErrors); notice != "" {
+		fmt.Fprintf(&content, "%s\n\n", notice)
+	}
 
 	// Add capabilities overview
 	content.WriteString("## Capabilities\n\n")
@@ -209,6 +219,9 @@ func generateRootIndex(info *model.ServerInfo, contentDir string, includeFrontma
 	}
 	if info.Capabilities.Resources {
 		fmt.Fprintf(&content, "- ✅ **Resources:** %d available\n", len(info.Resources))
+		if len(info.ResourceTemplates) > 0 {
+			fmt.Fprintf(&content, "- ✅ **Resource Templates:** %d available\n", len(info.ResourceTemplates))
+		}
 	}
 	if info.Capabilities.Prompts {
 		fmt.Fprintf(&content, "- ✅ **Prompts:** %d available\n", len(info.Prompts))
@@ -222,6 +235,9 @@ func generateRootIndex(info *model.ServerInfo, contentDir string, includeFrontma
 	if len(info.Resources) > 0 {
 		content.WriteString("- [Resources]({{< ref \"resources\" >}}) - Available MCP resources\n")
 	}
+	if len(info.ResourceTemplates) > 0 {
+		content.WriteString("- [Resource Templates]({{< ref \"resource-templates\" >}}) - Available MCP resource templates\n")
+	}
 	if len(info.Prompts) > 0 {
 		content.WriteString("- [Prompts]({{< ref \"prompts\" >}}) - Available MCP prompts\n")
 	}
@@ -275,6 +291,28 @@ func generateResourcesSection(info *model.ServerInfo, contentDir string, include
 	return nil
 }
 
+// generateResourceTemplatesSection creates the resource templates directory and all resource template markdown files
+func generateResourceTemplatesSection(info *model.ServerInfo, contentDir string, includeFrontmatter bool, frontmatterFormat string, customFields map[string]any, generationTime time.Time, customInitialisms []string, templateFS embed.FS) error {
+	templatesDir := filepath.Join(contentDir, "resource-templates")
+	if err := os.MkdirAll(templatesDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create resource templates directory: %w", err)
+	}
+
+	// Generate resource templates section index
+	if err := generateSectionIndex(templatesDir, "Resource Templates", "Available MCP resource templates and their documentation", len(info.ResourceTemplates), includeFrontmatter, frontmatterFormat, customFields, info, generationTime); err != nil {
+		return err
+	}
+
+	// Generate individual resource template files
+	for i, template := range info.ResourceTemplates {
+		if err := generateContentFile(templatesDir, &template, template.Name, "resource_template", i+1, includeFrontmatter, frontmatterFormat, customFields, info, generationTime, customInitialisms, templateFS); err != nil {
+			return fmt.Errorf("failed to generate resource template file for %s: %w", template.Name, err)
+		}
+	}
+
+	return nil
+}
+
 // generatePromptsSection creates the prompts directory and all prompt markdown files
 func generatePromptsSection(info *model.ServerInfo, contentDir string, includeFrontmatter bool, frontmatterFormat string, customFields map[string]any, generationTime time.Time, customInitialisms []string, templateFS embed.FS) error {
 	promptsDir := filepath.Join(contentDir, "prompts")
@@ -348,6 +386,8 @@ func createHugoTemplateFuncMap(customInitialisms []string) template.FuncMap {
 	return template.FuncMap{
 		"json":       jsonIndent,
 		"contains":   strings.Contains,
+		"codeBlock":  codeBlock,
+		"blockquote": blockquote,
 		"sortedKeys": getSortedKeys,
 		"humanize": func(s string) string {
 			return humanizeKeyWithCustomInitialisms(s, customInitialisms)
@@ -443,6 +483,8 @@ func getSectionWeight(title string) int {
 		return 10
 	case "resources":
 		return 20
+	case "resource templates":
+		return 25
 	case "prompts":
 		return 30
 	default:
@@ -139,6 +139,160 @@ func TestFormatHugo(t *testing.T) {
 	})
 }
 
+func TestFormatHugoResourceTemplates(t *testing.T) {
+	info := &model.ServerInfo{
+		Name:         "Template Server",
+		Capabilities: model.Capabilities{Resources: true},
+		ResourceTemplates: []model.ResourceTemplate{
+			{
+				URITemplate: "file:///{path}",
+				Name:        "project_file",
+				Description: "A file in the project",
+				MimeType:    "text/plain",
+			},
+		},
+	}
+
+	tempDir := t.TempDir()
+	if err := FormatHugo(info, tempDir, false, "", nil, &HugoConfig{}, nil, testHugoTemplateFS); err != nil {
+		t.Fatalf("FormatHugo failed: %v", err)
+	}
+
+	sectionDir := filepath.Join(tempDir, "content", "resource-templates")
+	verifyFileExists(t, filepath.Join(sectionDir, "_index.md"))
+	verifyFileExists(t, filepath.Join(sectionDir, "project-file.md"))
+
+	content, err := os.ReadFile(filepath.Join(sectionDir, "project-file.md"))
+	if err != nil {
+		t.Fatalf("Failed to read resource template file: %v", err)
+	}
+	if !strings.Contains(string(content), "`file:///{path}`") {
+		t.Errorf("Resource template page should contain the URI template, got:\n%s", content)
+	}
+}
+
+func TestFormatHugoCollectionErrors(t *testing.T) {
+	info := &model.ServerInfo{
+		Name:             "Partial Server",
+		Capabilities:     model.Capabilities{Tools: true},
+		CollectionErrors: []model.CollectionError{{Section: "tools", Error: "Bad Gateway"}},
+	}
+
+	tempDir := t.TempDir()
+	if err := FormatHugo(info, tempDir, true, "yaml", nil, &HugoConfig{}, nil, testHugoTemplateFS); err != nil {
+		t.Fatalf("FormatHugo failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "content", "_index.md"))
+	if err != nil {
+		t.Fatalf("Failed to read root index: %v", err)
+	}
+	for _, want := range []string{"collection_errors:", "Collection errors:", "**tools:** Bad Gateway"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Root index should contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestFormatHugoToolAnnotations(t *testing.T) {
+	info := &model.ServerInfo{
+		Name:         "Annotated Server",
+		Capabilities: model.Capabilities{Tools: true},
+		Tools: []model.Tool{
+			{
+				Name:         "delete_file",
+				Title:        "Delete File",
+				Description:  "Deletes a file",
+				InputSchema:  map[string]any{"type": "object"},
+				OutputSchema: map[string]any{"type": "object", "properties": map[string]any{"deleted": map[string]any{"type": "boolean"}}},
+				Annotations:  &model.ToolAnnotations{},
+			},
+		},
+	}
+
+	tempDir := t.TempDir()
+	if err := FormatHugo(info, tempDir, false, "", nil, &HugoConfig{}, nil, testHugoTemplateFS); err != nil {
+		t.Fatalf("FormatHugo failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "content", "tools", "delete-file.md"))
+	if err != nil {
+		t.Fatalf("Failed to read tool file: %v", err)
+	}
+
+	for _, want := range []string{"*Delete File*", "`destructive`", "## Annotations", "| Destructive | yes |", "## Output Schema", `"deleted"`} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Tool page should contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestFormatHugoResourceContents(t *testing.T) {
+	info := &model.ServerInfo{
+		Name:         "Content Server",
+		Capabilities: model.Capabilities{Resources: true},
+		Resources: []model.Resource{
+			{
+				URI:      "file:///config.json",
+				Name:     "config",
+				MimeType: "application/json",
+				Contents: []model.ResourceContent{
+					{URI: "file:///config.json", MimeType: "application/json", Text: `{"debug": true}`, Size: 15},
+					{URI: "file:///logo.png", MimeType: "image/png", IsBlob: true, Size: 2048, SHA256: "deadbeef"},
+				},
+			},
+		},
+	}
+
+	tempDir := t.TempDir()
+	if err := FormatHugo(info, tempDir, false, "", nil, &HugoConfig{}, nil, testHugoTemplateFS); err != nil {
+		t.Fatalf("FormatHugo failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "content", "resources", "config.md"))
+	if err != nil {
+		t.Fatalf("Failed to read resource file: %v", err)
+	}
+
+	for _, want := range []string{"## Contents", "```json\n{\"debug\": true}\n```", "**Binary Content:** 2048 bytes", "`deadbeef`"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Resource page should contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestFormatHugoPromptMessages(t *testing.T) {
+	info := &model.ServerInfo{
+		Name:         "Prompt Server",
+		Capabilities: model.Capabilities{Prompts: true},
+		Prompts: []model.Prompt{
+			{
+				Name:            "review_code",
+				SampleArguments: map[string]string{"language": "go"},
+				Messages: []model.PromptMessage{
+					{Role: "user", Text: "Review this go code:\n\nfunc main() {}"},
+				},
+			},
+		},
+	}
+
+	tempDir := t.TempDir()
+	if err := FormatHugo(info, tempDir, false, "", nil, &HugoConfig{}, nil, testHugoTemplateFS); err != nil {
+		t.Fatalf("FormatHugo failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "content", "prompts", "review-code.md"))
+	if err != nil {
+		t.Fatalf("Failed to read prompt file: %v", err)
+	}
+
+	for _, want := range []string{"## Messages", "`language=go`", "> **User:**", "> Review this go code:\n>\n> func main() {}"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Prompt page should contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
 func TestFormatHugoErrorPaths(t *testing.T) {
 	info := &model.ServerInfo{
 		Name:    "Test Server",
@@ -240,6 +394,7 @@ func TestGetSectionWeight(t *testing.T) {
 		{"TOOLS", 10},
 		{"Resources", 20},
 		{"resources", 20},
+		{"Resource Templates", 25},
 		{"Prompts", 30},
 		{"prompts", 30},
 		{"Other", 100},
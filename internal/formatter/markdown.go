@@ -25,11 +25,14 @@ func FormatMarkdown(info *model.ServerInfo, includeTOC, includeFrontmatter bool,
 
 	// Create template with custom functions
 	tmpl := template.New("base.md.tmpl").Funcs(template.FuncMap{
-		"anchor":     anchorName,
-		"json":       jsonIndent,
-		"jsonIndent": jsonIndent,
-		"formatBool": formatBool,
-		"contains":   strings.Contains,
+		"anchor":                 anchorName,
+		"json":                   jsonIndent,
+		"jsonIndent":             jsonIndent,
+		"formatBool":             formatBool,
+		"contains":               strings.Contains,
+		"codeBlock":              codeBlock,
+		"blockquote":             blockquote,
+		"collectionErrorsNotice": collectionErrorsNotice,
 		"humanizeKey": func(key string) string {
 			return humanizeKeyWithCustomInitialisms(key, customInitialisms)
 		},
@@ -62,6 +62,7 @@ func FormatPDF(info *model.ServerInfo, includeTOC bool) ([]byte, error) {
 	pdf := initializePDF()
 
 	addPDFTitle(pdf, info)
+	addCollectionErrorsNotice(pdf, info)
 
 	if includeTOC {
 		addTableOfContents(pdf, info)
@@ -70,6 +71,7 @@ func FormatPDF(info *model.ServerInfo, includeTOC bool) ([]byte, error) {
 	addCapabilitiesSection(pdf, info)
 	addToolsSection(pdf, info)
 	addResourcesSection(pdf, info)
+	addResourceTemplatesSection(pdf, info)
 	addPromptsSection(pdf, info)
 
 	return finalizePDF(pdf)
@@ -101,6 +103,24 @@ func addPDFTitle(pdf *fpdf.Fpdf, info *model.ServerInfo) {
 	pdf.Ln(smallSpacing)
 }
 
+// addCollectionErrorsNotice warns that the document is incomplete when sections could not be collected
+func addCollectionErrorsNotice(pdf *fpdf.Fpdf, info *model.ServerInfo) {
+	if len(info.CollectionErrors) == 0 {
+		return
+	}
+
+	pdf.SetTextColor(warningRed[0], warningRed[1], warningRed[2])
+	pdf.SetFont("DejaVuSans", "", 12)
+	pdf.MultiCell(0, 6, crossMark+" Collection errors: this documentation is incomplete. The following sections could not be collected in full:", "", "", false)
+	pdf.Ln(2)
+
+	pdf.SetFont("DejaVuSans", "", 10)
+	for _, collectionErr := range info.CollectionErrors {
+		pdf.MultiCell(0, 5, fmt.Sprintf("%s %s: %s", bulletPoint, collectionErr.Section, collectionErr.Error), "", "", false)
+	}
+	pdf.Ln(sectionSpacing)
+}
+
 // addTableOfContents adds the table of contents section
 func addTableOfContents(pdf *fpdf.Fpdf, info *model.ServerInfo) {
 	pdf.SetTextColor(primaryBlue[0], primaryBlue[1], primaryBlue[2])
@@ -128,6 +148,11 @@ func addTableOfContents(pdf *fpdf.Fpdf, info *model.ServerInfo) {
 		pdf.Ln(itemSpacing)
 	}
 
+	if info.Capabilities.Resources && len(info.ResourceTemplates) > 0 {
+		pdf.Cell(0, 8, "  "+bulletPoint+" Resource Templates")
+		pdf.Ln(itemSpacing)
+	}
+
 	if info.Capabilities.Prompts && len(info.Prompts) > 0 {
 		pdf.Cell(0, 8, "  "+bulletPoint+" Prompts")
 		pdf.Ln(itemSpacing)
@@ -202,17 +227,36 @@ func renderTool(pdf *fpdf.Fpdf, tool model.Tool) {
 	pdf.SetTextColor(64, 64, 64)
 	pdf.SetFont("DejaVuSans", "", 10)
 
+	if tool.Title != "" {
+		pdf.Cell(0, 6, tool.Title)
+		pdf.Ln(itemSpacing)
+	}
+
+	renderToolBadges(pdf, tool)
+
 	if tool.Description != "" {
 		pdf.Cell(0, 6, tool.Description)
 		pdf.Ln(itemSpacing)
 	}
 
+	if tool.Annotations != nil {
+		pdf.Cell(0, 6, "Annotations:")
+		pdf.Ln(itemSpacing)
+		renderToolAnnotations(pdf, tool.Annotations)
+	}
+
 	if tool.InputSchema != nil {
 		pdf.Cell(0, 6, "Input Schema:")
 		pdf.Ln(itemSpacing)
 		renderJSONSchema(pdf, tool.InputSchema)
 	}
 
+	if tool.OutputSchema != nil {
+		pdf.Cell(0, 6, "Output Schema:")
+		pdf.Ln(itemSpacing)
+		renderJSONSchema(pdf, tool.OutputSchema)
+	}
+
 	if len(tool.Context) > 0 {
 		pdf.Cell(0, 6, "Context:")
 		pdf.Ln(itemSpacing)
@@ -222,6 +266,46 @@ func renderTool(pdf *fpdf.Fpdf, tool model.Tool) {
 	pdf.Ln(subsectionSpacing)
 }
 
+// renderToolBadges renders the annotation badges for a tool, highlighting destructive tools
+func renderToolBadges(pdf *fpdf.Fpdf, tool model.Tool) {
+	badges := tool.Badges()
+	if len(badges) == 0 {
+		return
+	}
+
+	if tool.Annotations.IsDestructive() {
+		pdf.SetTextColor(warningRed[0], warningRed[1], warningRed[2])
+	}
+	pdf.Cell(0, 6, "["+strings.Join(badges, "] [")+"]")
+	pdf.Ln(itemSpacing)
+	pdf.SetTextColor(64, 64, 64)
+}
+
+// renderToolAnnotations renders the effective annotation hints as a two-column table
+func renderToolAnnotations(pdf *fpdf.Fpdf, annotations *model.ToolAnnotations) {
+	rows := []struct {
+		hint  string
+		value bool
+	}{
+		{"Read-only", annotations.IsReadOnly()},
+		{"Destructive", annotations.IsDestructive()},
+		{"Idempotent", annotations.IsIdempotent()},
+		{"Open world", annotations.IsOpenWorld()},
+	}
+
+	pdf.SetFont("DejaVuSans", "", 9)
+	for _, row := range rows {
+		value := "no"
+		if row.value {
+			value = "yes"
+		}
+		pdf.CellFormat(40, 5, row.hint, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(20, 5, value, "1", 1, "L", false, 0, "")
+	}
+	pdf.SetFont("DejaVuSans", "", 10)
+	pdf.Ln(smallSpacing)
+}
+
 // addResourcesSection adds the resources section to the PDF
 func addResourcesSection(pdf *fpdf.Fpdf, info *model.ServerInfo) {
 	if !info.Capabilities.Resources || len(info.Resources) == 0 {
@@ -261,6 +345,15 @@ func renderResource(pdf *fpdf.Fpdf, resource model.Resource) {
 		pdf.Ln(itemSpacing)
 	}
 
+	if resource.ReadError != "" {
+		pdf.Cell(0, 6, fmt.Sprintf("Read Error: %s", resource.ReadError))
+		pdf.Ln(itemSpacing)
+	}
+
+	for _, content := range resource.Contents {
+		renderResourceContent(pdf, content)
+	}
+
 	if len(resource.Context) > 0 {
 		pdf.Cell(0, 6, "Context:")
 		pdf.Ln(itemSpacing)
@@ -270,6 +363,87 @@ func renderResource(pdf *fpdf.Fpdf, resource model.Resource) {
 	pdf.Ln(subsectionSpacing)
 }
 
+// renderResourceContent renders text content line by line, or a size/hash summary for blobs and skipped content
+func renderResourceContent(pdf *fpdf.Fpdf, content model.ResourceContent) {
+	summary := fmt.Sprintf("%d bytes, SHA-256 %s", content.Size, content.SHA256)
+	switch {
+	case content.Skipped != "":
+		pdf.Cell(0, 6, fmt.Sprintf("Content not included: %s (%s)", content.Skipped, summary))
+		pdf.Ln(itemSpacing)
+		return
+	case content.IsBlob:
+		pdf.Cell(0, 6, fmt.Sprintf("Binary Content: %s", summary))
+		pdf.Ln(itemSpacing)
+		return
+	}
+
+	pdf.Cell(0, 6, "Contents:")
+	pdf.Ln(itemSpacing)
+
+	pdf.SetFont("DejaVuSans", "", 9)
+	for line := range strings.SplitSeq(strings.TrimSuffix(content.Text, "\n"), "\n") {
+		if len(line) > maxJSONLineLength {
+			line = line[:maxJSONLineLength-3] + "..."
+		}
+		pdf.Cell(0, 4, line)
+		pdf.Ln(4)
+	}
+	pdf.SetFont("DejaVuSans", "", 10)
+
+	if content.Truncated {
+		pdf.Cell(0, 6, fmt.Sprintf("Truncated: showing %d of %d bytes", len(content.Text), content.Size))
+		pdf.Ln(itemSpacing)
+	}
+}
+
+// addResourceTemplatesSection adds the resource templates section to the PDF
+func addResourceTemplatesSection(pdf *fpdf.Fpdf, info *model.ServerInfo) {
+	if !info.Capabilities.Resources || len(info.ResourceTemplates) == 0 {
+		return
+	}
+
+	pdf.SetTextColor(primaryBlue[0], primaryBlue[1], primaryBlue[2])
+	pdf.SetFont("DejaVuSans", "", 14)
+	pdf.Bookmark("Resource Templates", 0, 0)
+	pdf.Cell(0, 10, "Resource Templates")
+	pdf.Ln(sectionSpacing)
+
+	for _, template := range info.ResourceTemplates {
+		renderResourceTemplate(pdf, template)
+	}
+}
+
+// renderResourceTemplate renders a single resource template in the PDF
+func renderResourceTemplate(pdf *fpdf.Fpdf, template model.ResourceTemplate) {
+	pdf.SetTextColor(textGray[0], textGray[1], textGray[2])
+	pdf.SetFont("DejaVuSans", "", 12)
+	pdf.Cell(0, 8, template.Name)
+	pdf.Ln(subsectionSpacing)
+
+	pdf.SetTextColor(64, 64, 64)
+	pdf.SetFont("DejaVuSans", "", 10)
+	pdf.Cell(0, 6, fmt.Sprintf("URI Template: %s", template.URITemplate))
+	pdf.Ln(itemSpacing)
+
+	if template.Description != "" {
+		pdf.Cell(0, 6, template.Description)
+		pdf.Ln(itemSpacing)
+	}
+
+	if template.MimeType != "" {
+		pdf.Cell(0, 6, fmt.Sprintf("MIME Type: %s", template.MimeType))
+		pdf.Ln(itemSpacing)
+	}
+
+	if len(template.Context) > 0 {
+		pdf.Cell(0, 6, "Context:")
+		pdf.Ln(itemSpacing)
+		renderContext(pdf, template.Context)
+	}
+
+	pdf.Ln(subsectionSpacing)
+}
+
 // addPromptsSection adds the prompts section to the PDF
 func addPromptsSection(pdf *fpdf.Fpdf, info *model.ServerInfo) {
 	if !info.Capabilities.Prompts || len(info.Prompts) == 0 {
@@ -310,6 +484,17 @@ func renderPrompt(pdf *fpdf.Fpdf, prompt model.Prompt) {
 		}
 	}
 
+	if prompt.GetError != "" {
+		pdf.Cell(0, 6, fmt.Sprintf("Get Prompt Error: %s", prompt.GetError))
+		pdf.Ln(itemSpacing)
+	}
+
+	if len(prompt.Messages) > 0 {
+		pdf.Cell(0, 6, "Messages:")
+		pdf.Ln(itemSpacing)
+		renderPromptMessages(pdf, prompt.Messages)
+	}
+
 	if len(prompt.Context) > 0 {
 		pdf.Cell(0, 6, "Context:")
 		pdf.Ln(itemSpacing)
@@ -319,6 +504,30 @@ func renderPrompt(pdf *fpdf.Fpdf, prompt model.Prompt) {
 	pdf.Ln(subsectionSpacing)
 }
 
+// renderPromptMessages renders prompt messages as a conversation, one role-labelled block per message
+func renderPromptMessages(pdf *fpdf.Fpdf, messages []model.PromptMessage) {
+	for _, message := range messages {
+		pdf.SetTextColor(primaryBlue[0], primaryBlue[1], primaryBlue[2])
+		pdf.SetFont("DejaVuSans", "", 10)
+		pdf.Cell(0, 6, humanizeKey(message.Role)+":")
+		pdf.Ln(itemSpacing)
+
+		pdf.SetTextColor(64, 64, 64)
+		if message.Text == "" {
+			renderJSONSchema(pdf, message.Content)
+			continue
+		}
+
+		pdf.SetFont("DejaVuSans", "", 9)
+		for line := range strings.SplitSeq(strings.TrimSuffix(message.Text, "\n"), "\n") {
+			pdf.SetX(15)
+			pdf.MultiCell(0, 4, line, "", "L", false)
+		}
+		pdf.Ln(2)
+	}
+	pdf.SetFont("DejaVuSans", "", 10)
+}
+
 // finalizePDF completes the PDF generation and returns the bytes
 func finalizePDF(pdf *fpdf.Fpdf) ([]byte, error) {
 	if !pdf.Ok() {
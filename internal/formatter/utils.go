@@ -9,6 +9,8 @@ import (
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+
+	"github.com/spandigital/mcp-server-dump/internal/model"
 )
 
 const (
@@ -167,6 +169,88 @@ func formatBool(b bool) string {
 	return "❌ Not supported"
 }
 
+// codeLanguages maps MIME subtypes to fenced code block languages
+var codeLanguages = map[string]string{
+	"json":        "json",
+	"ld+json":     "json",
+	"schema+json": "json",
+	"yaml":        "yaml",
+	"x-yaml":      "yaml",
+	"xml":         "xml",
+	"html":        "html",
+	"markdown":    "markdown",
+	"javascript":  "javascript",
+	"x-python":    "python",
+	"x-sh":        "sh",
+	"csv":         "csv",
+	"toml":        "toml",
+	"css":         "css",
+}
+
+// codeLanguage returns the fenced code block language for a MIME type, or an empty string if unknown
+func codeLanguage(mimeType string) string {
+	mediaType, _, _ := strings.Cut(strings.ToLower(mimeType), ";")
+	_, subtype, found := strings.Cut(strings.TrimSpace(mediaType), "/")
+	if !found {
+		return ""
+	}
+	if lang, ok := codeLanguages[subtype]; ok {
+		return lang
+	}
+	if strings.HasSuffix(subtype, "+json") {
+		return "json"
+	}
+	if strings.HasSuffix(subtype, "+xml") {
+		return "xml"
+	}
+	return ""
+}
+
+// codeBlock wraps text in a fenced code block, choosing a fence longer than any backtick run in the text
+func codeBlock(text, mimeType string) string {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	return fence + codeLanguage(mimeType) + "\n" + strings.TrimSuffix(text, "\n") + "\n" + fence
+}
+
+// blockquote prefixes every line of text with a markdown blockquote marker
+func blockquote(text string) string {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = ">"
+		} else {
+			lines[i] = "> " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// collectionErrorsNotice renders a Markdown warning listing the sections that could not be
+// collected, or an empty string when the dump is complete
+func collectionErrorsNotice(collectionErrors []model.CollectionError) string {
+	if len(collectionErrors) == 0 {
+		return ""
+	}
+	var notice strings.Builder
+	notice.WriteString("**⚠️ Collection errors:** this documentation is incomplete. ")
+	notice.WriteString("The following sections could not be collected in full:\n\n")
+	for _, collectionErr := range collectionErrors {
+		// Keep each error on its list item
+		message := strings.Join(strings.Fields(collectionErr.Error), " ")
+		notice.WriteString("- **" + collectionErr.Section + ":** " + message + "\n")
+	}
+	return blockquote(notice.String())
+}
+
 // isAlphaNumeric reports whether the character is alphanumeric or underscore.
 // Used for word boundary checking in JSON parsing to handle identifiers like "true_value".
 func isAlphaNumeric(char byte) bool {
@@ -1,7 +1,10 @@
 package formatter
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/spandigital/mcp-server-dump/internal/model"
 )
 
 func TestHumanizeKey(t *testing.T) {
@@ -299,3 +302,53 @@ func TestHumanizeKeyWithRealWorldExamples(t *testing.T) {
 		})
 	}
 }
+
+func TestCodeBlock(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		mimeType string
+		want     string
+	}{
+		{"json", `{"a":1}`, "application/json", "```json\n{\"a\":1}\n```"},
+		{"suffix_json", "{}", "application/vnd.api+json; charset=utf-8", "```json\n{}\n```"},
+		{"unknown_type", "plain\n", "text/plain", "```\nplain\n```"},
+		{"nested_fence", "```go\nx\n```", "text/markdown", "````markdown\n```go\nx\n```\n````"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := codeBlock(tt.text, tt.mimeType); got != tt.want {
+				t.Errorf("codeBlock() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockquote(t *testing.T) {
+	got := blockquote("first\n\nsecond\n")
+	want := "> first\n>\n> second"
+	if got != want {
+		t.Errorf("blockquote() = %q, want %q", got, want)
+	}
+}
+
+func TestCollectionErrorsNotice(t *testing.T) {
+	if notice := collectionErrorsNotice(nil); notice != "" {
+		t.Errorf("Expected no notice for a complete dump, got %q", notice)
+	}
+
+	notice := collectionErrorsNotice([]model.CollectionError{
+		{Section: "tools", Error: "Bad Gateway\n(after 3 attempts)"},
+		{Section: "prompts", Error: "method not found"},
+	})
+	for _, want := range []string{
+		"> **⚠️ Collection errors:**",
+		"> - **tools:** Bad Gateway (after 3 attempts)",
+		"> - **prompts:** method not found",
+	} {
+		if !strings.Contains(notice, want) {
+			t.Errorf("Expected notice to contain %q, got:\n%s", want, notice)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Expected tool call outcomes
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// CallsConfig represents the structure of a tool calls file
+type CallsConfig struct {
+	Calls []ToolCallSpec `yaml:"calls" json:"calls"`
+}
+
+// ToolCallSpec describes a single tool call to make. The same tool may appear
+// several times with different arguments.
+type ToolCallSpec struct {
+	Tool      string `yaml:"tool" json:"tool"`
+	Label     string `yaml:"label" json:"label"`
+	Arguments any    `yaml:"arguments" json:"arguments"`
+	// Expect is the expected outcome: "success", "error", or empty for no expectation
+	Expect string `yaml:"expect" json:"expect"`
+}
+
+// LoadCallsFile loads and validates a YAML or JSON tool calls file
+func LoadCallsFile(filename string) (*CallsConfig, error) {
+	cleanPath, err := validateFilePath(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := validateFileSize(cleanPath); err != nil {
+		return nil, err
+	}
+
+	data, err := readContextFile(cleanPath)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := parseCallsFile(data, cleanPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load calls file %s: %w", filename, err)
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid calls file %s: %w", filename, err)
+	}
+
+	return config, nil
+}
+
+// parseCallsFile parses the calls file based on its extension
+func parseCallsFile(data []byte, cleanPath string) (*CallsConfig, error) {
+	config := &CallsConfig{}
+	ext := strings.ToLower(filepath.Ext(cleanPath))
+
+	switch ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+		// yaml.v2 decodes nested mappings as map[any]any, which cannot be sent as JSON arguments
+		for i := range config.Calls {
+			config.Calls[i].Arguments = normalizeYAML(config.Calls[i].Arguments)
+		}
+	case ".json":
+		if err := json.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported file format: %s (supported: .yaml, .yml, .json)", ext)
+	}
+
+	return config, nil
+}
+
+// validate checks that every call names a tool and uses a known expected outcome
+func (c *CallsConfig) validate() error {
+	if len(c.Calls) == 0 {
+		return errors.New("no calls defined")
+	}
+
+	for i, call := range c.Calls {
+		if call.Tool == "" {
+			return fmt.Errorf("call %d: tool name is required", i+1)
+		}
+		switch call.Expect {
+		case "", OutcomeSuccess, OutcomeError:
+		default:
+			return fmt.Errorf("call %d (%s): invalid expect value %q (supported: %s, %s)", i+1, call.Tool, call.Expect, OutcomeSuccess, OutcomeError)
+		}
+	}
+
+	return nil
+}
+
+// normalizeYAML converts map[any]any values produced by yaml.v2 into map[string]any recursively
+func normalizeYAML(value any) any {
+	switch v := value.(type) {
+	case map[any]any:
+		normalized := make(map[string]any, len(v))
+		for key, item := range v {
+			normalized[fmt.Sprint(key)] = normalizeYAML(item)
+		}
+		return normalized
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeYAML(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadCallsFile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	t.Run("yaml_with_nested_arguments", func(t *testing.T) {
+		content := `
+calls:
+  - tool: get_weather
+    label: London
+    arguments:
+      location: London
+      options:
+        units: metric
+  - tool: get_weather
+    label: Unknown city
+    arguments:
+      location: Atlantis
+    expect: error
+`
+		path := filepath.Join(tempDir, "calls.yaml")
+		if err := os.WriteFile(path, []byte(content), testFilePermissions); err != nil {
+			t.Fatalf("Failed to create calls file: %v", err)
+		}
+
+		config, err := LoadCallsFile(path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(config.Calls) != 2 {
+			t.Fatalf("Expected 2 calls, got %d", len(config.Calls))
+		}
+		if config.Calls[1].Label != "Unknown city" || config.Calls[1].Expect != OutcomeError {
+			t.Errorf("Unexpected second call: %+v", config.Calls[1])
+		}
+
+		// Arguments must be JSON-encodable to be sent to the server
+		data, err := json.Marshal(config.Calls[0].Arguments)
+		if err != nil {
+			t.Fatalf("Arguments should be JSON-encodable: %v", err)
+		}
+		if !strings.Contains(string(data), `"units":"metric"`) {
+			t.Errorf("Expected nested arguments to survive, got %s", data)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		content := `{"calls": [{"tool": "search", "arguments": {"query": "mcp"}, "expect": "success"}]}`
+		path := filepath.Join(tempDir, "calls.json")
+		if err := os.WriteFile(path, []byte(content), testFilePermissions); err != nil {
+			t.Fatalf("Failed to create calls file: %v", err)
+		}
+
+		config, err := LoadCallsFile(path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if config.Calls[0].Tool != "search" {
+			t.Errorf("Expected search tool, got %q", config.Calls[0].Tool)
+		}
+	})
+
+	t.Run("invalid_files", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			file    string
+			content string
+			wantErr string
+		}{
+			{"missing_tool", "missing.yaml", "calls:\n  - label: nothing\n", "tool name is required"},
+			{"bad_expect", "expect.yaml", "calls:\n  - tool: a\n    expect: maybe\n", "invalid expect value"},
+			{"no_calls", "empty.yaml", "calls: []\n", "no calls defined"},
+			{"bad_extension", "calls.txt", "calls: []\n", "unsupported file format"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				path := filepath.Join(tempDir, tt.file)
+				if err := os.WriteFile(path, []byte(tt.content), testFilePermissions); err != nil {
+					t.Fatalf("Failed to create calls file: %v", err)
+				}
+				_, err := LoadCallsFile(path)
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+			})
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"cmp"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"gopkg.in/yaml.v2"
@@ -21,10 +23,14 @@ const (
 // ContextConfig represents the structure of context configuration files
 type ContextConfig struct {
 	Contexts struct {
-		Tools     map[string]map[string]string `yaml:"tools" json:"tools"`
-		Resources map[string]map[string]string `yaml:"resources" json:"resources"`
-		Prompts   map[string]map[string]string `yaml:"prompts" json:"prompts"`
+		Tools             map[string]map[string]string `yaml:"tools" json:"tools"`
+		Resources         map[string]map[string]string `yaml:"resources" json:"resources"`
+		ResourceTemplates map[string]map[string]string `yaml:"resource_templates" json:"resource_templates"`
+		Prompts           map[string]map[string]string `yaml:"prompts" json:"prompts"`
 	} `yaml:"contexts" json:"contexts"`
+
+	// PromptArguments supplies the arguments used when fetching prompt messages, keyed by prompt name
+	PromptArguments map[string]map[string]string `yaml:"prompt_arguments" json:"prompt_arguments"`
 }
 
 // LoadContextConfig loads and merges multiple context files
@@ -32,7 +38,9 @@ func LoadContextConfig(files []string) (*ContextConfig, error) {
 	config := &ContextConfig{}
 	config.Contexts.Tools = make(map[string]map[string]string)
 	config.Contexts.Resources = make(map[string]map[string]string)
+	config.Contexts.ResourceTemplates = make(map[string]map[string]string)
 	config.Contexts.Prompts = make(map[string]map[string]string)
+	config.PromptArguments = make(map[string]map[string]string)
 
 	for _, file := range files {
 		if err := config.mergeFile(file); err != nil {
@@ -130,7 +138,9 @@ func parseContextFile(data []byte, cleanPath string) (config ContextConfig, err
 func (c *ContextConfig) mergeContextData(tempConfig *ContextConfig) {
 	c.mergeTools(tempConfig.Contexts.Tools)
 	c.mergeResources(tempConfig.Contexts.Resources)
+	c.mergeResourceTemplates(tempConfig.Contexts.ResourceTemplates)
 	c.mergePrompts(tempConfig.Contexts.Prompts)
+	c.mergePromptArguments(tempConfig.PromptArguments)
 }
 
 // mergeTools merges tool contexts from the temporary config
@@ -153,6 +163,16 @@ func (c *ContextConfig) mergeResources(resources map[string]map[string]string) {
 	}
 }
 
+// mergeResourceTemplates merges resource template contexts from the temporary config
+func (c *ContextConfig) mergeResourceTemplates(templates map[string]map[string]string) {
+	for templatePattern, contexts := range templates {
+		if c.Contexts.ResourceTemplates[templatePattern] == nil {
+			c.Contexts.ResourceTemplates[templatePattern] = make(map[string]string)
+		}
+		maps.Copy(c.Contexts.ResourceTemplates[templatePattern], contexts)
+	}
+}
+
 // mergePrompts merges prompt contexts from the temporary config
 func (c *ContextConfig) mergePrompts(prompts map[string]map[string]string) {
 	for promptName, contexts := range prompts {
@@ -163,6 +183,24 @@ func (c *ContextConfig) mergePrompts(prompts map[string]map[string]string) {
 	}
 }
 
+// mergePromptArguments merges prompt arguments from the temporary config
+func (c *ContextConfig) mergePromptArguments(arguments map[string]map[string]string) {
+	for promptName, args := range arguments {
+		if c.PromptArguments[promptName] == nil {
+			c.PromptArguments[promptName] = make(map[string]string)
+		}
+		maps.Copy(c.PromptArguments[promptName], args)
+	}
+}
+
+// PromptArgumentsFor returns the configured arguments for a prompt, or nil if none are configured
+func (c *ContextConfig) PromptArgumentsFor(promptName string) map[string]string {
+	if c == nil {
+		return nil
+	}
+	return c.PromptArguments[promptName]
+}
+
 // ApplyToTool applies matching context to a tool
 func (c *ContextConfig) ApplyToTool(tool *Tool) {
 	if contexts, exists := c.Contexts.Tools[tool.Name]; exists {
@@ -175,25 +213,52 @@ func (c *ContextConfig) ApplyToTool(tool *Tool) {
 
 // ApplyToResource applies matching context to a resource using pattern matching
 func (c *ContextConfig) ApplyToResource(resource *Resource) {
-	for pattern, contexts := range c.Contexts.Resources {
-		var matched bool
+	if contexts, ok := matchingURIContext(c.Contexts.Resources, resource.URI); ok {
+		if resource.Context == nil {
+			resource.Context = make(map[string]string)
+		}
+		maps.Copy(resource.Context, contexts)
+	}
+}
 
-		// Try custom URI pattern matching first
+// ApplyToResourceTemplate applies matching context to a resource template using pattern matching
+// against its URI template (e.g. "file:///{path}" or "file://*")
+func (c *ContextConfig) ApplyToResourceTemplate(template *ResourceTemplate) {
+	if contexts, ok := matchingURIContext(c.Contexts.ResourceTemplates, template.URITemplate); ok {
+		if template.Context == nil {
+			template.Context = make(map[string]string)
+		}
+		maps.Copy(template.Context, contexts)
+	}
+}
+
+// matchingURIContext returns the context of the single best pattern matching the URI, so output does
+// not depend on map iteration order. An exact match wins; otherwise the longest wildcard pattern wins,
+// with ties broken alphabetically.
+func matchingURIContext(patterns map[string]map[string]string, uri string) (map[string]string, bool) {
+	if contexts, ok := patterns[uri]; ok {
+		return contexts, true
+	}
+
+	wildcards := make([]string, 0, len(patterns))
+	for pattern := range patterns {
 		if strings.Contains(pattern, "*") {
-			matched = matchURIPattern(pattern, resource.URI)
-		} else {
-			// Exact string match
-			matched = pattern == resource.URI
+			wildcards = append(wildcards, pattern)
+		}
+	}
+	slices.SortFunc(wildcards, func(a, b string) int {
+		if n := cmp.Compare(len(b), len(a)); n != 0 {
+			return n
 		}
+		return strings.Compare(a, b)
+	})
 
-		if matched {
-			if resource.Context == nil {
-				resource.Context = make(map[string]string)
-			}
-			maps.Copy(resource.Context, contexts)
-			break // Apply first matching pattern only
+	for _, pattern := range wildcards {
+		if matchURIPattern(pattern, uri) {
+			return patterns[pattern], true
 		}
 	}
+	return nil, false
 }
 
 // matchURIPattern matches URI patterns like "file://*" against URIs like "file:///path/file.txt"
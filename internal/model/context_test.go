@@ -312,6 +312,75 @@ func TestContextConfig_ApplyToResource(t *testing.T) {
 	})
 }
 
+func TestContextConfig_ApplyToResourceTemplate(t *testing.T) {
+	config := &ContextConfig{}
+	config.Contexts.ResourceTemplates = make(map[string]map[string]string)
+	config.Contexts.ResourceTemplates["file:///{path}"] = map[string]string{
+		"usage": "Substitute path with an absolute file path",
+	}
+	config.Contexts.ResourceTemplates["db://*"] = map[string]string{
+		"access": "Database records",
+	}
+
+	t.Run("exact_template_match", func(t *testing.T) {
+		template := &ResourceTemplate{URITemplate: "file:///{path}"}
+		config.ApplyToResourceTemplate(template)
+
+		if template.Context["usage"] != "Substitute path with an absolute file path" {
+			t.Errorf("Expected usage context, got '%s'", template.Context["usage"])
+		}
+	})
+
+	t.Run("wildcard_template_match", func(t *testing.T) {
+		template := &ResourceTemplate{URITemplate: "db://{table}/{id}"}
+		config.ApplyToResourceTemplate(template)
+
+		if template.Context["access"] != "Database records" {
+			t.Errorf("Expected access 'Database records', got '%s'", template.Context["access"])
+		}
+	})
+
+	t.Run("no_match", func(t *testing.T) {
+		template := &ResourceTemplate{URITemplate: "http://{host}"}
+		config.ApplyToResourceTemplate(template)
+
+		if template.Context != nil {
+			t.Errorf("Expected nil context for non-matching template, got %+v", template.Context)
+		}
+	})
+
+	t.Run("overlapping_patterns", func(t *testing.T) {
+		overlapping := &ContextConfig{}
+		overlapping.Contexts.ResourceTemplates = map[string]map[string]string{
+			"file://*":               {"scope": "any file"},
+			"file:///docs/*":         {"scope": "docs"},
+			"*{name}.md":             {"scope": "markdown"},
+			"file:///docs/{name}.md": {"scope": "exact"},
+		}
+
+		tests := []struct {
+			uriTemplate string
+			want        string
+		}{
+			{"file:///docs/{name}.md", "exact"},
+			{"file:///docs/{id}", "docs"},
+			{"file:///src/{name}.md", "markdown"},
+			{"file:///src/{path}", "any file"},
+		}
+
+		// Repeat to catch map iteration order leaking into the result
+		for range 20 {
+			for _, tt := range tests {
+				template := &ResourceTemplate{URITemplate: tt.uriTemplate}
+				overlapping.ApplyToResourceTemplate(template)
+				if got := template.Context["scope"]; got != tt.want {
+					t.Fatalf("%s: scope = %q, want %q", tt.uriTemplate, got, tt.want)
+				}
+			}
+		}
+	})
+}
+
 func TestContextConfig_ApplyToPrompt(t *testing.T) {
 	config := &ContextConfig{}
 	config.Contexts.Prompts = make(map[string]map[string]string)
@@ -405,3 +474,49 @@ func TestMatchURIPattern(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadContextConfig_PromptArguments(t *testing.T) {
+	tempDir := t.TempDir()
+
+	baseContent := `
+prompt_arguments:
+  analyze_code:
+    language: go
+`
+	overrideContent := `
+prompt_arguments:
+  analyze_code:
+    code: "fmt.Println(1)"
+  summarize:
+    length: short
+`
+	basePath := filepath.Join(tempDir, "base.yaml")
+	overridePath := filepath.Join(tempDir, "override.yaml")
+	if err := os.WriteFile(basePath, []byte(baseContent), testFilePermissions); err != nil {
+		t.Fatalf("Failed to create base file: %v", err)
+	}
+	if err := os.WriteFile(overridePath, []byte(overrideContent), testFilePermissions); err != nil {
+		t.Fatalf("Failed to create override file: %v", err)
+	}
+
+	config, err := LoadContextConfig([]string{basePath, overridePath})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	args := config.PromptArgumentsFor("analyze_code")
+	if args["language"] != "go" || args["code"] != "fmt.Println(1)" {
+		t.Errorf("Expected merged prompt arguments, got %v", args)
+	}
+	if config.PromptArgumentsFor("summarize")["length"] != "short" {
+		t.Errorf("Expected summarize arguments, got %v", config.PromptArgumentsFor("summarize"))
+	}
+	if config.PromptArgumentsFor("unknown") != nil {
+		t.Error("Expected nil arguments for unconfigured prompt")
+	}
+
+	var nilConfig *ContextConfig
+	if nilConfig.PromptArgumentsFor("analyze_code") != nil {
+		t.Error("Expected nil arguments from nil config")
+	}
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// maxDumpFileSize limits JSON dumps to 100MB; dumps with resource contents can be large
+const maxDumpFileSize = 100 * 1024 * 1024
+
+// LoadServerInfo reads a JSON dump produced by the JSON formatter back into a ServerInfo.
+// A filename of "-" reads the dump from standard input.
+func LoadServerInfo(filename string) (*ServerInfo, error) {
+	var reader io.Reader
+	if filename == "-" {
+		reader = os.Stdin
+	} else {
+		cleanPath, err := validateFilePath(filename)
+		if err != nil {
+			return nil, err
+		}
+		file, err := os.Open(cleanPath) // #nosec G304 - path is validated and cleaned
+		if err != nil {
+			return nil, fmt.Errorf("failed to open dump: %w", err)
+		}
+		defer func() {
+			if closeErr := file.Close(); closeErr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to close dump file: %v\n", closeErr)
+			}
+		}()
+		reader = file
+	}
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxDumpFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dump %s: %w", filename, err)
+	}
+	if len(data) > maxDumpFileSize {
+		return nil, fmt.Errorf("dump %s exceeds maximum allowed size of %d bytes", filename, maxDumpFileSize)
+	}
+
+	var info ServerInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse dump %s: %w", filename, err)
+	}
+	if info.Name == "" && len(info.Tools) == 0 && len(info.Resources) == 0 && len(info.Prompts) == 0 {
+		return nil, fmt.Errorf("%s does not look like a server dump (no name, tools, resources, or prompts)", filename)
+	}
+
+	return &info, nil
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadServerInfo(t *testing.T) {
+	dir := t.TempDir()
+
+	original := &ServerInfo{
+		Name:    "test-server",
+		Version: "1.0.0",
+		Tools: []Tool{{
+			Name:        "echo",
+			InputSchema: map[string]any{"type": "object"},
+		}},
+		Prompts: []Prompt{{
+			Name:      "review",
+			Arguments: []any{map[string]any{"name": "code", "required": true}},
+		}},
+	}
+	data, err := json.MarshalIndent(original, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to encode dump: %v", err)
+	}
+	dumpPath := filepath.Join(dir, "dump.json")
+	if err := os.WriteFile(dumpPath, data, 0o600); err != nil {
+		t.Fatalf("Failed to write dump: %v", err)
+	}
+
+	info, err := LoadServerInfo(dumpPath)
+	if err != nil {
+		t.Fatalf("LoadServerInfo failed: %v", err)
+	}
+	if info.Name != "test-server" || info.Version != "1.0.0" || len(info.Tools) != 1 {
+		t.Errorf("Unexpected server info: %+v", info)
+	}
+	if args := info.Prompts[0].ParsedArguments(); len(args) != 1 || !args[0].Required {
+		t.Errorf("Expected prompt arguments to survive the round trip, got %+v", args)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"invalid_json", "{not json", "failed to parse dump"},
+		{"not_a_dump", `{"foo": "bar"}`, "does not look like a server dump"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+			_, err := LoadServerInfo(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if _, err := LoadServerInfo(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SchemaMap normalises a JSON Schema of any Go representation, such as the SDK's
+// *jsonschema.Schema or a map decoded from a dump, into a generic JSON map.
+// A nil schema gives an empty map, which accepts anything.
+func SchemaMap(schema any) (map[string]any, error) {
+	if schema == nil {
+		return map[string]any{}, nil
+	}
+	if m, ok := schema.(map[string]any); ok {
+		return m, nil
+	}
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode schema: %w", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to decode schema: %w", err)
+	}
+	if m == nil {
+		return map[string]any{}, nil
+	}
+	return m, nil
+}
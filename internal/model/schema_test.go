@@ -0,0 +1,35 @@
+package model
+
+import "testing"
+
+func TestSchemaMap(t *testing.T) {
+	type schema struct {
+		Type     string   `json:"type"`
+		Required []string `json:"required,omitempty"`
+	}
+
+	got, err := SchemaMap(&schema{Type: "object", Required: []string{"name"}})
+	if err != nil {
+		t.Fatalf("SchemaMap failed: %v", err)
+	}
+	if got["type"] != "object" || len(got["required"].([]any)) != 1 {
+		t.Errorf("Unexpected schema map %v", got)
+	}
+
+	for _, empty := range []any{nil, nullSchema{}} {
+		if got, err := SchemaMap(empty); err != nil || len(got) != 0 {
+			t.Errorf("Expected an empty map for %T, got %v, %v", empty, got, err)
+		}
+	}
+
+	if _, err := SchemaMap("not a schema"); err == nil {
+		t.Error("Expected an error for a schema that is not a JSON object")
+	}
+}
+
+// nullSchema marshals to JSON null, as a nil pointer schema does
+type nullSchema struct{}
+
+func (nullSchema) MarshalJSON() ([]byte, error) {
+	return []byte("null"), nil
+}
@@ -1,14 +1,46 @@
 package model
 
+import "encoding/json"
+
 // ServerInfo represents information about an MCP server
 type ServerInfo struct {
-	Name         string       `json:"name"`
-	Version      string       `json:"version"`
-	Capabilities Capabilities `json:"capabilities"`
-	Tools        []Tool       `json:"tools"`
-	Resources    []Resource   `json:"resources"`
-	Prompts      []Prompt     `json:"prompts"`
-	ToolCalls    []ToolCall   `json:"toolCalls,omitempty"`
+	Name              string             `json:"name"`
+	Version           string             `json:"version"`
+	Transport         string             `json:"transport,omitempty"`
+	Capabilities      Capabilities       `json:"capabilities"`
+	Tools             []Tool             `json:"tools"`
+	Resources         []Resource         `json:"resources"`
+	ResourceTemplates []ResourceTemplate `json:"resourceTemplates,omitempty"`
+	Prompts           []Prompt           `json:"prompts"`
+	ToolCalls         []ToolCall         `json:"toolCalls,omitempty"`
+	Pagination        *Pagination        `json:"pagination,omitempty"`
+	CollectionErrors  []CollectionError  `json:"collectionErrors,omitempty"`
+}
+
+// CollectionError records a section that could not be collected in full.
+// Items listed before the failure are kept, so the section may be partially populated.
+type CollectionError struct {
+	Section string `json:"section"`
+	Error   string `json:"error"`
+}
+
+// AddCollectionError records that section failed to collect with err
+func (s *ServerInfo) AddCollectionError(section string, err error) {
+	s.CollectionErrors = append(s.CollectionErrors, CollectionError{Section: section, Error: err.Error()})
+}
+
+// Partial reports whether any section failed to collect or was not listed in full
+func (s *ServerInfo) Partial() bool {
+	return len(s.CollectionErrors) > 0 || (s.Pagination != nil && len(s.Pagination.Truncated) > 0)
+}
+
+// Pagination records how many list pages were fetched for each section
+type Pagination struct {
+	ToolPages             int      `json:"toolPages,omitempty"`
+	ResourcePages         int      `json:"resourcePages,omitempty"`
+	ResourceTemplatePages int      `json:"resourceTemplatePages,omitempty"`
+	PromptPages           int      `json:"promptPages,omitempty"`
+	Truncated             []string `json:"truncated,omitempty"`
 }
 
 // Capabilities represents the capabilities of an MCP server
@@ -20,10 +52,76 @@ type Capabilities struct {
 
 // Tool represents an MCP tool
 type Tool struct {
-	Name        string            `json:"name"`
-	Description string            `json:"description"`
-	InputSchema any               `json:"inputSchema"`
-	Context     map[string]string `json:"context,omitempty"`
+	Name         string            `json:"name"`
+	Title        string            `json:"title,omitempty"`
+	Description  string            `json:"description"`
+	InputSchema  any               `json:"inputSchema"`
+	OutputSchema any               `json:"outputSchema,omitempty"`
+	Annotations  *ToolAnnotations  `json:"annotations,omitempty"`
+	Context      map[string]string `json:"context,omitempty"`
+}
+
+// ToolAnnotations holds the behavioural hints a server advertises for a tool.
+// DestructiveHint and OpenWorldHint are pointers because the MCP specification
+// defaults them to true when they are omitted.
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    bool   `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	IdempotentHint  bool   `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
+}
+
+// IsReadOnly reports whether the tool claims not to modify its environment
+func (a *ToolAnnotations) IsReadOnly() bool {
+	return a != nil && a.ReadOnlyHint
+}
+
+// IsDestructive reports whether the tool may perform destructive updates,
+// applying the specification default (true) when the hint is omitted
+func (a *ToolAnnotations) IsDestructive() bool {
+	if a == nil || a.ReadOnlyHint {
+		return false
+	}
+	return a.DestructiveHint == nil || *a.DestructiveHint
+}
+
+// IsIdempotent reports whether repeated calls with the same arguments have no additional effect
+func (a *ToolAnnotations) IsIdempotent() bool {
+	return a != nil && !a.ReadOnlyHint && a.IdempotentHint
+}
+
+// IsOpenWorld reports whether the tool may interact with external entities,
+// applying the specification default (true) when the hint is omitted
+func (a *ToolAnnotations) IsOpenWorld() bool {
+	if a == nil {
+		return false
+	}
+	return a.OpenWorldHint == nil || *a.OpenWorldHint
+}
+
+// Badges returns short labels summarising the tool's annotations, suitable for
+// rendering next to the tool name. Tools without annotations have no badges.
+func (t Tool) Badges() []string {
+	a := t.Annotations
+	if a == nil {
+		return nil
+	}
+
+	var badges []string
+	if a.IsReadOnly() {
+		badges = append(badges, "read-only")
+	}
+	if a.IsDestructive() {
+		badges = append(badges, "destructive")
+	}
+	if a.IsIdempotent() {
+		badges = append(badges, "idempotent")
+	}
+	if a.IsOpenWorld() {
+		badges = append(badges, "open-world")
+	}
+	return badges
 }
 
 // Resource represents an MCP resource
@@ -33,6 +131,33 @@ type Resource struct {
 	Description string            `json:"description"`
 	MimeType    string            `json:"mimeType"`
 	Context     map[string]string `json:"context,omitempty"`
+	Contents    []ResourceContent `json:"contents,omitempty"`
+	ReadError   string            `json:"readError,omitempty"`
+}
+
+// ResourceContent holds one content item returned when reading a resource.
+// Exactly one of Text or Blob is populated unless the content was skipped.
+// Size and SHA256 always describe the full content as returned by the server,
+// even when Text or Blob were truncated or dropped.
+type ResourceContent struct {
+	URI       string `json:"uri"`
+	MimeType  string `json:"mimeType,omitempty"`
+	Text      string `json:"text,omitempty"`
+	Blob      []byte `json:"blob,omitempty"`
+	IsBlob    bool   `json:"isBlob,omitempty"`
+	Size      int    `json:"size"`
+	SHA256    string `json:"sha256,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"`
+	Skipped   string `json:"skipped,omitempty"`
+}
+
+// ResourceTemplate represents a parameterized MCP resource described by an RFC 6570 URI template
+type ResourceTemplate struct {
+	URITemplate string            `json:"uriTemplate"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	MimeType    string            `json:"mimeType,omitempty"`
+	Context     map[string]string `json:"context,omitempty"`
 }
 
 // Prompt represents an MCP prompt
@@ -41,13 +166,66 @@ type Prompt struct {
 	Description string            `json:"description"`
 	Arguments   []any             `json:"arguments"`
 	Context     map[string]string `json:"context,omitempty"`
+
+	// Populated when prompt messages are fetched with GetPrompt
+	SampleArguments map[string]string `json:"sampleArguments,omitempty"`
+	Messages        []PromptMessage   `json:"messages,omitempty"`
+	GetError        string            `json:"getError,omitempty"`
+}
+
+// PromptArgument describes a single argument accepted by a prompt
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// ParsedArguments decodes the prompt's argument metadata. Arguments are stored as []any so they
+// may be SDK values from a live server or generic maps from a JSON dump; both round-trip through JSON.
+// Entries without a name are skipped.
+func (p Prompt) ParsedArguments() []PromptArgument {
+	var parsed []PromptArgument
+	for _, raw := range p.Arguments {
+		data, err := json.Marshal(raw)
+		if err != nil {
+			continue
+		}
+		var arg PromptArgument
+		if err := json.Unmarshal(data, &arg); err != nil || arg.Name == "" {
+			continue
+		}
+		parsed = append(parsed, arg)
+	}
+	return parsed
+}
+
+// PromptMessage represents a single message returned by GetPrompt.
+// Text holds text content; any other content type is kept as returned in Content.
+type PromptMessage struct {
+	Role    string `json:"role"`
+	Text    string `json:"text,omitempty"`
+	Content any    `json:"content,omitempty"`
 }
 
 // ToolCall represents the result of calling an MCP tool
 type ToolCall struct {
 	ToolName          string `json:"toolName"`
+	Label             string `json:"label,omitempty"`
 	Arguments         any    `json:"arguments,omitempty"`
 	Content           []any  `json:"content,omitempty"`
 	StructuredContent any    `json:"structuredContent,omitempty"`
+	IsError           bool   `json:"isError,omitempty"`
 	Error             string `json:"error,omitempty"`
+	ExpectedOutcome   string `json:"expectedOutcome,omitempty"`
+	OutcomeMismatch   bool   `json:"outcomeMismatch,omitempty"`
+
+	// Validation of the arguments and structured result against the tool's declared schemas
+	InputValidationErrors  []string `json:"inputValidationErrors,omitempty"`
+	OutputValidationErrors []string `json:"outputValidationErrors,omitempty"`
+}
+
+// Failed reports whether the call failed, either at the protocol level or as a tool error result
+func (c ToolCall) Failed() bool {
+	return c.Error != "" || c.IsError
 }
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTool_Badges(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+
+	tests := []struct {
+		name        string
+		annotations *ToolAnnotations
+		want        []string
+	}{
+		{
+			name: "no_annotations",
+			want: nil,
+		},
+		{
+			name:        "spec_defaults",
+			annotations: &ToolAnnotations{},
+			want:        []string{"destructive", "open-world"},
+		},
+		{
+			name:        "read_only_ignores_destructive_and_idempotent",
+			annotations: &ToolAnnotations{ReadOnlyHint: true, DestructiveHint: boolPtr(true), IdempotentHint: true, OpenWorldHint: boolPtr(false)},
+			want:        []string{"read-only"},
+		},
+		{
+			name:        "additive_idempotent_closed_world",
+			annotations: &ToolAnnotations{DestructiveHint: boolPtr(false), IdempotentHint: true, OpenWorldHint: boolPtr(false)},
+			want:        []string{"idempotent"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := Tool{Name: "tool", Annotations: tt.annotations}
+			if got := tool.Badges(); !slices.Equal(got, tt.want) {
+				t.Errorf("Expected badges %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPrompt_ParsedArguments(t *testing.T) {
+	type sdkArgument struct {
+		Name     string `json:"name"`
+		Required bool   `json:"required,omitempty"`
+	}
+
+	prompt := Prompt{
+		Arguments: []any{
+			&sdkArgument{Name: "language", Required: true},
+			map[string]any{"name": "focus", "description": "Area to focus on"},
+			map[string]any{"description": "missing name"},
+		},
+	}
+
+	args := prompt.ParsedArguments()
+	if len(args) != 2 {
+		t.Fatalf("Expected 2 parsed arguments, got %d: %+v", len(args), args)
+	}
+	if args[0].Name != "language" || !args[0].Required {
+		t.Errorf("Unexpected first argument: %+v", args[0])
+	}
+	if args[1].Name != "focus" || args[1].Required || args[1].Description != "Area to focus on" {
+		t.Errorf("Unexpected second argument: %+v", args[1])
+	}
+}
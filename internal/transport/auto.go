@@ -0,0 +1,229 @@
+package transport
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/spandigital/mcp-server-dump/internal/auth"
+	"github.com/spandigital/mcp-server-dump/internal/configdir"
+)
+
+// DetectedTransport records the transport auto-detection selected for an endpoint
+type DetectedTransport struct {
+	Endpoint   string    `json:"endpoint"`
+	Transport  string    `json:"transport"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// ConnectAuto connects to config.Endpoint for --transport auto, following the MCP backwards
+// compatibility guidance: initialize is sent over Streamable HTTP first and, if the server
+// answers with a 4xx status, the client falls back to the legacy SSE transport.
+// The selected transport is cached per endpoint so later runs connect directly; a cached
+// choice that stops working is discarded and detection runs again.
+// It returns the session and the name of the selected transport.
+func ConnectAuto(ctx context.Context, client *mcp.Client, config *Config, oauthConfig *auth.Config) (*mcp.ClientSession, string, error) {
+	if config.Endpoint == "" {
+		return nil, "", fmt.Errorf("auto transport requires --endpoint")
+	}
+
+	base, err := NewHTTPTransport(config)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// ws:// and wss:// URLs can only be WebSocket endpoints
+	if HTTPEndpoint(config.Endpoint) != config.Endpoint {
+		mcpTransport, err := createWebSocketTransport(config, oauthConfig, base)
+		if err != nil {
+			return nil, "", err
+		}
+		session, err := client.Connect(ctx, mcpTransport, nil)
+		return session, "websocket", err
+	}
+
+	if cached, err := LoadDetectedTransport(config.Endpoint); err != nil {
+		log.Printf("Warning: ignoring transport cache: %v", err)
+	} else if cached != nil {
+		session, connErr := connectWith(ctx, client, config, oauthConfig, cached.Transport, base)
+		if connErr == nil {
+			log.Printf("Using cached %s transport for %s", cached.Transport, config.Endpoint)
+			return session, cached.Transport, nil
+		}
+		log.Printf("Cached %s transport failed for %s, detecting again: %v", cached.Transport, config.Endpoint, connErr)
+		if clearErr := ClearDetectedTransport(config.Endpoint); clearErr != nil {
+			log.Printf("Warning: %v", clearErr)
+		}
+	}
+
+	selected := "streamable"
+	recorder := &statusRecorder{base: base}
+	session, err := connectWith(ctx, client, config, oauthConfig, selected, recorder)
+	if err != nil {
+		status := recorder.lastStatus()
+		if !fallBackToSSE(status) {
+			return nil, "", fmt.Errorf("streamable HTTP transport failed: %w", err)
+		}
+
+		log.Printf("Streamable HTTP initialize returned %d, falling back to SSE transport", status)
+		selected = "sse"
+		session, err = connectWith(ctx, client, config, oauthConfig, selected, base)
+		if err != nil {
+			return nil, "", fmt.Errorf("neither streamable HTTP (status %d) nor SSE transport worked: %w", status, err)
+		}
+	}
+
+	log.Printf("Detected %s transport for %s", selected, config.Endpoint)
+	if err := SaveDetectedTransport(config.Endpoint, selected); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	return session, selected, nil
+}
+
+// connectWith creates the named HTTP transport over base and initializes a session with it
+func connectWith(ctx context.Context, client *mcp.Client, config *Config, oauthConfig *auth.Config, name string, base http.RoundTripper) (*mcp.ClientSession, error) {
+	var mcpTransport mcp.Transport
+	var err error
+	switch name {
+	case "streamable":
+		mcpTransport, err = createStreamableTransport(config, oauthConfig, base)
+	case "sse":
+		mcpTransport, err = createSSETransport(config, oauthConfig, base)
+	default:
+		return nil, fmt.Errorf("unsupported detected transport: %s", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return client.Connect(ctx, mcpTransport, nil)
+}
+
+// fallBackToSSE reports whether a Streamable HTTP initialize status indicates a legacy server.
+// Authentication failures are not retried over SSE since they would fail the same way, and no
+// status at all means the endpoint could not be reached.
+func fallBackToSSE(status int) bool {
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return false
+	}
+	return status >= 400 && status < 500
+}
+
+// statusRecorder remembers the status of the most recent POST, which is how Streamable HTTP
+// sends initialize
+type statusRecorder struct {
+	base   http.RoundTripper
+	mu     sync.Mutex
+	status int
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *statusRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.base.RoundTrip(req)
+	if err == nil && req.Method == http.MethodPost {
+		r.mu.Lock()
+		r.status = resp.StatusCode
+		r.mu.Unlock()
+	}
+	return resp, err
+}
+
+// lastStatus returns the recorded status, or 0 if no POST received a response
+func (r *statusRecorder) lastStatus() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+// transportCacheDir returns the directory for detected transports: transports/ in the
+// configuration directory (see configdir.Dir)
+func transportCacheDir() (string, error) {
+	return configdir.Sub("transports")
+}
+
+// transportCachePath returns the cache file for an endpoint, named by a hash of the endpoint
+func transportCachePath(endpoint string) (string, error) {
+	dir, err := transportCacheDir()
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256([]byte(endpoint))
+	return filepath.Join(dir, hex.EncodeToString(hash[:8])+".json"), nil
+}
+
+// LoadDetectedTransport returns the cached transport for an endpoint, or nil if none is cached
+func LoadDetectedTransport(endpoint string) (*DetectedTransport, error) {
+	cachePath, err := transportCachePath(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// #nosec G304 - cachePath is derived from a hash of the endpoint
+	data, err := os.ReadFile(cachePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transport cache: %w", err)
+	}
+
+	var detected DetectedTransport
+	if err := json.Unmarshal(data, &detected); err != nil {
+		return nil, fmt.Errorf("failed to parse transport cache: %w", err)
+	}
+	if detected.Endpoint != endpoint {
+		return nil, fmt.Errorf("transport cache endpoint mismatch")
+	}
+	return &detected, nil
+}
+
+// SaveDetectedTransport caches the transport selected for an endpoint
+func SaveDetectedTransport(endpoint, transport string) error {
+	cacheDir, err := transportCacheDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cacheDir, 0o700); err != nil {
+		return fmt.Errorf("failed to create transport cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(DetectedTransport{
+		Endpoint:   endpoint,
+		Transport:  transport,
+		DetectedAt: time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal transport cache: %w", err)
+	}
+
+	cachePath, err := transportCachePath(endpoint)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(cachePath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write transport cache: %w", err)
+	}
+	return nil
+}
+
+// ClearDetectedTransport removes the cached transport for an endpoint
+func ClearDetectedTransport(endpoint string) error {
+	cachePath, err := transportCachePath(endpoint)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(cachePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove transport cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/spandigital/mcp-server-dump/internal/configdir"
+)
+
+func newAutoTestServer(t *testing.T, transport string) *httptest.Server {
+	t.Helper()
+
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "auto-test-server", Version: "1.0.0"}, nil)
+	getServer := func(*http.Request) *mcp.Server { return mcpServer }
+
+	var handler http.Handler
+	switch transport {
+	case "streamable":
+		handler = mcp.NewStreamableHTTPHandler(getServer, nil)
+	case "sse":
+		handler = mcp.NewSSEHandler(getServer, nil)
+	default:
+		t.Fatalf("unknown test transport %q", transport)
+	}
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func connectAuto(t *testing.T, endpoint string) (string, error) {
+	t.Helper()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, selected, err := ConnectAuto(context.Background(), client, &Config{Transport: "auto", Endpoint: endpoint}, nil)
+	if err != nil {
+		return "", err
+	}
+	if name := session.InitializeResult().ServerInfo.Name; name != "auto-test-server" {
+		t.Errorf("Expected server name auto-test-server, got %q", name)
+	}
+	_ = session.Close()
+	return selected, nil
+}
+
+func TestConnectAuto(t *testing.T) {
+	for _, want := range []string{"streamable", "sse"} {
+		t.Run(want, func(t *testing.T) {
+			t.Setenv(configdir.HomeEnv, t.TempDir())
+			server := newAutoTestServer(t, want)
+
+			selected, err := connectAuto(t, server.URL)
+			if err != nil {
+				t.Fatalf("ConnectAuto failed: %v", err)
+			}
+			if selected != want {
+				t.Errorf("Expected %s transport, got %s", want, selected)
+			}
+
+			cached, err := LoadDetectedTransport(server.URL)
+			if err != nil {
+				t.Fatalf("LoadDetectedTransport failed: %v", err)
+			}
+			if cached == nil || cached.Transport != want {
+				t.Fatalf("Expected %s to be cached, got %+v", want, cached)
+			}
+
+			// The cached choice is reused
+			if selected, err := connectAuto(t, server.URL); err != nil || selected != want {
+				t.Errorf("Expected cached %s transport, got %q (%v)", want, selected, err)
+			}
+		})
+	}
+}
+
+func TestConnectAuto_StaleCache(t *testing.T) {
+	t.Setenv(configdir.HomeEnv, t.TempDir())
+	server := newAutoTestServer(t, "streamable")
+
+	if err := SaveDetectedTransport(server.URL, "sse"); err != nil {
+		t.Fatalf("SaveDetectedTransport failed: %v", err)
+	}
+
+	selected, err := connectAuto(t, server.URL)
+	if err != nil {
+		t.Fatalf("ConnectAuto failed: %v", err)
+	}
+	if selected != "streamable" {
+		t.Errorf("Expected detection to select streamable, got %s", selected)
+	}
+
+	cached, err := LoadDetectedTransport(server.URL)
+	if err != nil || cached == nil || cached.Transport != "streamable" {
+		t.Errorf("Expected cache to be updated to streamable, got %+v (%v)", cached, err)
+	}
+}
+
+func TestConnectAuto_Unauthorized(t *testing.T) {
+	t.Setenv(configdir.HomeEnv, t.TempDir())
+	var mu sync.Mutex
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		methods = append(methods, r.Method)
+		mu.Unlock()
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	if _, err := connectAuto(t, server.URL); err == nil {
+		t.Fatal("Expected an error for an unauthorized endpoint")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, method := range methods {
+		if method == http.MethodGet {
+			t.Error("Expected no SSE fallback after a 401 response")
+		}
+	}
+
+	if cached, _ := LoadDetectedTransport(server.URL); cached != nil {
+		t.Errorf("Expected nothing cached after a failure, got %+v", cached)
+	}
+}
+
+func TestFallBackToSSE(t *testing.T) {
+	tests := map[int]bool{
+		0:                              false,
+		http.StatusBadRequest:          true,
+		http.StatusNotFound:            true,
+		http.StatusMethodNotAllowed:    true,
+		http.StatusUnauthorized:        false,
+		http.StatusForbidden:           false,
+		http.StatusInternalServerError: false,
+	}
+	for status, want := range tests {
+		if got := fallBackToSSE(status); got != want {
+			t.Errorf("fallBackToSSE(%d) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestCreate_AutoRequiresConnectAuto(t *testing.T) {
+	if _, err := Create(&Config{Transport: "auto", Endpoint: "http://localhost"}, nil); err == nil || !strings.Contains(err.Error(), "ConnectAuto") {
+		t.Errorf("Expected Create to reject auto, got %v", err)
+	}
+}
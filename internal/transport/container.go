@@ -0,0 +1,223 @@
+package transport
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// DefaultContainerNetwork isolates containerised servers from the network unless overridden
+const DefaultContainerNetwork = "none"
+
+// containerRemoveTimeout bounds how long cleanup waits for the container runtime
+const containerRemoveTimeout = 30 * time.Second
+
+// supportedContainerRuntimes lists the container CLIs that can run servers, in detection order
+var supportedContainerRuntimes = []string{"docker", "podman"}
+
+// createContainerTransport runs the server command inside a container using a local docker or
+// podman CLI with stdio attached. The container is named so it can be force-removed when the
+// connection closes or its context is cancelled, even if the runtime CLI itself is killed.
+func createContainerTransport(config *Config) (mcp.Transport, error) {
+	runtime, err := resolveContainerRuntime(config.ContainerRuntime)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := containerName()
+	if err != nil {
+		return nil, err
+	}
+
+	// Variables reach the container as "-e KEY" so their values never appear in the process list;
+	// the runtime CLI reads them from its own environment, which always inherits ours.
+	envConfig := *config
+	envConfig.ClearEnv = false
+	environ, configured, err := buildCommandEnv(&envConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := buildContainerArgs(config, name, configured)
+	if err != nil {
+		return nil, err
+	}
+
+	// #nosec G204 - Container runtime and arguments are provided by user intentionally
+	cmd := exec.Command(runtime, args...)
+	cmd.Env = environ
+	if config.Stderr != nil {
+		cmd.Stderr = config.Stderr
+	}
+
+	if len(configured) > 0 {
+		log.Printf("Starting server in %s container %s with environment: %s", runtime, name, redactEnv(configured))
+	} else {
+		log.Printf("Starting server in %s container %s", runtime, name)
+	}
+
+	return &containerTransport{
+		command: &mcp.CommandTransport{Command: cmd},
+		remove:  func() { removeContainer(runtime, name) },
+	}, nil
+}
+
+// buildContainerArgs builds the "run" arguments for the container runtime
+func buildContainerArgs(config *Config, name string, envKeys []string) ([]string, error) {
+	network := config.ContainerNetwork
+	if network == "" {
+		network = DefaultContainerNetwork
+	}
+
+	args := []string{"run", "--rm", "-i", "--name", name, "--network", network}
+
+	for _, mount := range config.ContainerMounts {
+		volume, err := parseContainerMount(mount)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "--volume", volume)
+	}
+
+	for _, key := range envKeys {
+		args = append(args, "--env", key)
+	}
+
+	if config.Cwd != "" {
+		args = append(args, "--workdir", config.Cwd)
+	}
+
+	args = append(args, config.ContainerImage)
+
+	switch {
+	case config.ServerCommand != "":
+		parts, err := splitShellWords(config.ServerCommand)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --server-command: %w", err)
+		}
+		args = append(args, parts...)
+	case len(config.Args) > 0:
+		args = append(args, config.Args...)
+	}
+	// With no command the image's default entrypoint runs
+
+	return args, nil
+}
+
+// parseContainerMount validates a "host:container[:ro|rw]" mount and makes the host path absolute,
+// as bind mounts require
+func parseContainerMount(mount string) (string, error) {
+	parts := strings.Split(mount, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("invalid --container-mount %q (expected host-path:container-path[:ro|rw])", mount)
+	}
+	if len(parts) == 3 && parts[2] != "ro" && parts[2] != "rw" {
+		return "", fmt.Errorf("invalid --container-mount %q: mode must be ro or rw", mount)
+	}
+	if !strings.HasPrefix(parts[1], "/") {
+		return "", fmt.Errorf("invalid --container-mount %q: container path must be absolute", mount)
+	}
+
+	hostPath, err := filepath.Abs(parts[0])
+	if err != nil {
+		return "", fmt.Errorf("invalid --container-mount %q: %w", mount, err)
+	}
+	if _, err := os.Stat(hostPath); err != nil {
+		return "", fmt.Errorf("invalid --container-mount %q: %w", mount, err)
+	}
+	parts[0] = hostPath
+	return strings.Join(parts, ":"), nil
+}
+
+// resolveContainerRuntime returns the path of the requested runtime, or detects docker or podman
+func resolveContainerRuntime(runtime string) (string, error) {
+	if runtime != "" {
+		path, err := exec.LookPath(runtime)
+		if err != nil {
+			return "", fmt.Errorf("container runtime %q not found: %w", runtime, err)
+		}
+		return path, nil
+	}
+
+	for _, candidate := range supportedContainerRuntimes {
+		if path, err := exec.LookPath(candidate); err == nil {
+			return path, nil
+		}
+	}
+	return "", errors.New("--container-image requires docker or podman on the PATH (or set --container-runtime)")
+}
+
+// containerName returns a unique name for the server container
+func containerName() (string, error) {
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate container name: %w", err)
+	}
+	return "mcp-server-dump-" + hex.EncodeToString(suffix), nil
+}
+
+// removeContainer force-removes the container. It is a no-op if --rm already removed it.
+func removeContainer(runtime, name string) {
+	ctx, cancel := context.WithTimeout(context.Background(), containerRemoveTimeout)
+	defer cancel()
+
+	// #nosec G204 - Container runtime is provided by user intentionally; name is generated
+	cmd := exec.CommandContext(ctx, runtime, "rm", "--force", name)
+	if output, err := cmd.CombinedOutput(); err != nil && !strings.Contains(string(output), "No such container") && !strings.Contains(string(output), "no such container") {
+		log.Printf("Warning: failed to remove container %s: %v: %s", name, err, strings.TrimSpace(string(output)))
+	}
+}
+
+// containerTransport connects through the container runtime and removes the container on close
+type containerTransport struct {
+	command mcp.Transport
+	remove  func()
+}
+
+// Connect starts the container. It is removed when the connection is closed or ctx is
+// cancelled, which main does on SIGINT/SIGTERM.
+func (t *containerTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	conn := &containerConnection{remove: t.remove}
+	conn.stop = context.AfterFunc(ctx, conn.cleanup)
+
+	inner, err := t.command.Connect(ctx)
+	if err != nil {
+		conn.stop()
+		conn.cleanup()
+		return nil, err
+	}
+	conn.Connection = inner
+	return conn, nil
+}
+
+// containerConnection removes its container exactly once, when closed or when its context is cancelled
+type containerConnection struct {
+	mcp.Connection
+	remove func()
+	stop   func() bool // stops watching the connection context
+	once   sync.Once
+}
+
+// Close closes the connection to the server, then removes the container
+func (c *containerConnection) Close() error {
+	c.stop()
+	err := c.Connection.Close()
+	c.cleanup()
+	return err
+}
+
+// cleanup removes the container. A concurrent call waits for the removal to finish.
+func (c *containerConnection) cleanup() {
+	c.once.Do(c.remove)
+}
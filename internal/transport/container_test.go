@@ -0,0 +1,219 @@
+package transport
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestBuildContainerArgs(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		config  Config
+		envKeys []string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:   "image_entrypoint_with_defaults",
+			config: Config{ContainerImage: "ghcr.io/example/server:1.0"},
+			want:   []string{"run", "--rm", "-i", "--name", "test", "--network", "none", "ghcr.io/example/server:1.0"},
+		},
+		{
+			name: "command_mounts_env_and_workdir",
+			config: Config{
+				ContainerImage:   "node:22",
+				ContainerNetwork: "bridge",
+				ContainerMounts:  []string{dir + ":/app:ro"},
+				ServerCommand:    `node "/app/my server.js"`,
+				Cwd:              "/app",
+			},
+			envKeys: []string{"API_KEY"},
+			want: []string{
+				"run", "--rm", "-i", "--name", "test", "--network", "bridge",
+				"--volume", dir + ":/app:ro", "--env", "API_KEY", "--workdir", "/app",
+				"node:22", "node", "/app/my server.js",
+			},
+		},
+		{
+			name:   "legacy_args",
+			config: Config{ContainerImage: "python:3.12", Args: []string{"python", "server.py"}},
+			want:   []string{"run", "--rm", "-i", "--name", "test", "--network", "none", "python:3.12", "python", "server.py"},
+		},
+		{
+			name:    "invalid_mount",
+			config:  Config{ContainerImage: "node:22", ContainerMounts: []string{"/only-host"}},
+			wantErr: "expected host-path:container-path",
+		},
+		{
+			name:    "invalid_mount_mode",
+			config:  Config{ContainerImage: "node:22", ContainerMounts: []string{dir + ":/app:rx"}},
+			wantErr: "mode must be ro or rw",
+		},
+		{
+			name:    "relative_container_path",
+			config:  Config{ContainerImage: "node:22", ContainerMounts: []string{dir + ":app"}},
+			wantErr: "container path must be absolute",
+		},
+		{
+			name:    "missing_host_path",
+			config:  Config{ContainerImage: "node:22", ContainerMounts: []string{filepath.Join(dir, "missing") + ":/app"}},
+			wantErr: "invalid --container-mount",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := buildContainerArgs(&tt.config, "test", tt.envKeys)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !slices.Equal(args, tt.want) {
+				t.Errorf("Expected args\n  %q\ngot\n  %q", tt.want, args)
+			}
+		})
+	}
+}
+
+func TestParseContainerMount_RelativeHostPath(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("data", 0o750); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	volume, err := parseContainerMount("data:/data")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	hostPath, err := filepath.Abs("data")
+	if err != nil {
+		t.Fatalf("Failed to resolve path: %v", err)
+	}
+	if want := hostPath + ":/data"; volume != want {
+		t.Errorf("Expected %q, got %q", want, volume)
+	}
+}
+
+func TestCreateContainerTransport_SecretsNotInArgs(t *testing.T) {
+	transport, err := createContainerTransport(&Config{
+		ContainerImage:   "node:22",
+		ContainerRuntime: "sh", // any executable on PATH stands in for the runtime
+		ServerCommand:    "node server.js",
+		Env:              []string{"API_KEY=super-secret"},
+		ClearEnv:         true,
+	})
+	if err != nil {
+		t.Fatalf("createContainerTransport failed: %v", err)
+	}
+
+	cmd := transport.(*containerTransport).command.(*mcp.CommandTransport).Command
+	if strings.Contains(strings.Join(cmd.Args, " "), "super-secret") {
+		t.Errorf("Secret value leaked into runtime arguments: %q", cmd.Args)
+	}
+	if !slices.Contains(cmd.Args, "API_KEY") {
+		t.Errorf("Expected --env API_KEY in runtime arguments, got %q", cmd.Args)
+	}
+	if !slices.Contains(cmd.Env, "API_KEY=super-secret") {
+		t.Error("Expected the runtime CLI environment to carry the value")
+	}
+	// The runtime CLI itself still needs the host environment, even with --clear-env
+	if os.Getenv("PATH") != "" && !slices.ContainsFunc(cmd.Env, func(e string) bool { return strings.HasPrefix(e, "PATH=") }) {
+		t.Error("Expected the runtime CLI environment to include PATH")
+	}
+}
+
+func TestResolveContainerRuntime_NotFound(t *testing.T) {
+	if _, err := resolveContainerRuntime("definitely-not-a-container-runtime"); err == nil {
+		t.Error("Expected error for missing runtime")
+	}
+}
+
+// stubConnection is a minimal mcp.Connection for exercising cleanup
+type stubConnection struct {
+	closed int
+}
+
+func (s *stubConnection) Read(context.Context) (jsonrpc.Message, error) { return nil, nil }
+func (s *stubConnection) Write(context.Context, jsonrpc.Message) error  { return nil }
+func (s *stubConnection) Close() error                                  { s.closed++; return nil }
+func (s *stubConnection) SessionID() string                             { return "" }
+
+// stubTransport returns a fixed connection or error
+type stubTransport struct {
+	conn mcp.Connection
+	err  error
+}
+
+func (s *stubTransport) Connect(context.Context) (mcp.Connection, error) { return s.conn, s.err }
+
+func TestContainerTransport_RemovesContainer(t *testing.T) {
+	t.Run("on_close", func(t *testing.T) {
+		removed := 0
+		inner := &stubConnection{}
+		transport := &containerTransport{command: &stubTransport{conn: inner}, remove: func() { removed++ }}
+
+		conn, err := transport.Connect(context.Background())
+		if err != nil {
+			t.Fatalf("Connect failed: %v", err)
+		}
+		_ = conn.Close()
+		_ = conn.Close()
+
+		if inner.closed != 2 {
+			t.Errorf("Expected inner connection to be closed on each Close, got %d", inner.closed)
+		}
+		if removed != 1 {
+			t.Errorf("Expected container to be removed exactly once, got %d", removed)
+		}
+	})
+
+	t.Run("on_context_cancel", func(t *testing.T) {
+		removed := make(chan struct{}, 2)
+		inner := &stubConnection{}
+		transport := &containerTransport{command: &stubTransport{conn: inner}, remove: func() { removed <- struct{}{} }}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		conn, err := transport.Connect(ctx)
+		if err != nil {
+			t.Fatalf("Connect failed: %v", err)
+		}
+		cancel()
+		select {
+		case <-removed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected container to be removed when the context is cancelled")
+		}
+
+		_ = conn.Close()
+		if len(removed) != 0 {
+			t.Error("Expected Close after cancellation not to remove the container again")
+		}
+	})
+
+	t.Run("on_connect_failure", func(t *testing.T) {
+		removed := 0
+		transport := &containerTransport{command: &stubTransport{err: context.Canceled}, remove: func() { removed++ }}
+
+		if _, err := transport.Connect(context.Background()); err == nil {
+			t.Fatal("Expected Connect to fail")
+		}
+		if removed != 1 {
+			t.Errorf("Expected container to be removed after a failed start, got %d", removed)
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package transport
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// maxEnvFileSize limits env files to 1MB
+const maxEnvFileSize = 1024 * 1024
+
+// buildCommandEnv builds the environment for the server process. Unless ClearEnv is set, the
+// current environment is inherited; env files are applied in order, then --env entries, with
+// later values replacing earlier ones. The sorted names of the explicitly configured variables
+// are returned separately so they can be logged without their values.
+func buildCommandEnv(config *Config) (environ, configured []string, err error) {
+	env := make(map[string]string)
+	var order []string
+	set := func(key, value string) {
+		if _, exists := env[key]; !exists {
+			order = append(order, key)
+		}
+		env[key] = value
+	}
+	explicit := make(map[string]bool)
+
+	if !config.ClearEnv {
+		for _, entry := range os.Environ() {
+			if key, value, ok := strings.Cut(entry, "="); ok {
+				set(key, value)
+			}
+		}
+	}
+
+	for _, file := range config.EnvFiles {
+		vars, err := parseEnvFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, v := range vars {
+			set(v[0], v[1])
+			explicit[v[0]] = true
+		}
+	}
+
+	for _, entry := range config.Env {
+		key, value, err := parseEnvEntry(entry)
+		if err != nil {
+			return nil, nil, err
+		}
+		set(key, value)
+		explicit[key] = true
+	}
+
+	environ = make([]string, 0, len(order))
+	for _, key := range order {
+		environ = append(environ, key+"="+env[key])
+	}
+	for key := range explicit {
+		configured = append(configured, key)
+	}
+	slices.Sort(configured)
+	return environ, configured, nil
+}
+
+// parseEnvEntry parses a --env entry. "KEY=VALUE" sets a value; a bare "KEY" passes the
+// variable through from the current environment, which is useful together with --clear-env.
+// Errors never include the value, since it is often a secret.
+func parseEnvEntry(entry string) (key, value string, err error) {
+	key, value, hasValue := strings.Cut(entry, "=")
+	key = strings.TrimSpace(key)
+	if !validEnvKey(key) {
+		if !hasValue {
+			// Without "=" the entry may be a misplaced value, so do not echo it
+			return "", "", errors.New("invalid --env entry: expected KEY=VALUE or the name of a variable to pass through")
+		}
+		return "", "", fmt.Errorf("invalid --env entry: %q is not a valid variable name (expected KEY=VALUE)", key)
+	}
+	if hasValue {
+		return key, value, nil
+	}
+
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", "", fmt.Errorf("--env %s: variable is not set in the current environment", key)
+	}
+	return key, value, nil
+}
+
+// parseEnvFile reads KEY=VALUE lines from a dotenv-style file. Blank lines and lines starting
+// with # are ignored, an optional "export " prefix is accepted, and values may be wrapped in
+// single or double quotes.
+func parseEnvFile(filename string) ([][2]string, error) {
+	cleanPath := filepath.Clean(filename)
+	fileInfo, err := os.Stat(cleanPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+	if fileInfo.Size() > maxEnvFileSize {
+		return nil, fmt.Errorf("env file %s exceeds maximum allowed size of %d bytes", filename, maxEnvFileSize)
+	}
+
+	file, err := os.Open(cleanPath) // #nosec G304 - env file path is provided by user intentionally
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to close env file: %v\n", closeErr)
+		}
+	}()
+
+	var vars [][2]string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEnvFileSize)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !validEnvKey(key) {
+			// Report the line number only: the line may contain a secret
+			return nil, fmt.Errorf("env file %s line %d: expected KEY=VALUE", filename, lineNumber)
+		}
+		vars = append(vars, [2]string{key, unquoteEnvValue(strings.TrimSpace(value))})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read env file %s: %w", filename, err)
+	}
+	return vars, nil
+}
+
+// unquoteEnvValue strips matching surrounding quotes. Double-quoted values support \n, \" and \\ escapes.
+func unquoteEnvValue(value string) string {
+	if len(value) < 2 {
+		return value
+	}
+	switch {
+	case value[0] == '\'' && value[len(value)-1] == '\'':
+		return value[1 : len(value)-1]
+	case value[0] == '"' && value[len(value)-1] == '"':
+		replacer := strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`)
+		return replacer.Replace(value[1 : len(value)-1])
+	}
+	return value
+}
+
+// validEnvKey reports whether key is a portable environment variable name
+func validEnvKey(key string) bool {
+	if key == "" || (key[0] >= '0' && key[0] <= '9') {
+		return false
+	}
+	for _, r := range key {
+		if r != '_' && (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// redactEnv formats variable names with masked values, suitable for logging
+func redactEnv(keys []string) string {
+	redacted := make([]string, len(keys))
+	for i, key := range keys {
+		redacted[i] = key + "=***"
+	}
+	return strings.Join(redacted, ", ")
+}
@@ -0,0 +1,154 @@
+package transport
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestBuildCommandEnv(t *testing.T) {
+	t.Setenv("MCP_DUMP_TEST_INHERITED", "inherited")
+	t.Setenv("MCP_DUMP_TEST_PASSTHROUGH", "passed")
+
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, ".env")
+	content := strings.Join([]string{
+		"# comment",
+		"",
+		"API_KEY=from-file",
+		"export REGION='eu-west-1'",
+		`GREETING="hello\nworld"`,
+		"MCP_DUMP_TEST_INHERITED=overridden-by-file",
+	}, "\n")
+	if err := os.WriteFile(envFile, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write env file: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		config         Config
+		want           map[string]string
+		absent         []string
+		wantConfigured []string
+	}{
+		{
+			name:   "inherit_and_override",
+			config: Config{EnvFiles: []string{envFile}, Env: []string{"API_KEY=from-flag", "EMPTY="}},
+			want: map[string]string{
+				"API_KEY":                 "from-flag",
+				"REGION":                  "eu-west-1",
+				"GREETING":                "hello\nworld",
+				"EMPTY":                   "",
+				"MCP_DUMP_TEST_INHERITED": "overridden-by-file",
+			},
+			wantConfigured: []string{"API_KEY", "EMPTY", "GREETING", "MCP_DUMP_TEST_INHERITED", "REGION"},
+		},
+		{
+			name:           "clear_env_with_pass_through",
+			config:         Config{ClearEnv: true, Env: []string{"TOKEN=abc=def", "MCP_DUMP_TEST_PASSTHROUGH"}},
+			want:           map[string]string{"TOKEN": "abc=def", "MCP_DUMP_TEST_PASSTHROUGH": "passed"},
+			absent:         []string{"MCP_DUMP_TEST_INHERITED"},
+			wantConfigured: []string{"MCP_DUMP_TEST_PASSTHROUGH", "TOKEN"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, configured, err := buildCommandEnv(&tt.config)
+			if err != nil {
+				t.Fatalf("buildCommandEnv failed: %v", err)
+			}
+
+			values := make(map[string]string)
+			for _, entry := range env {
+				key, value, _ := strings.Cut(entry, "=")
+				if _, dup := values[key]; dup {
+					t.Errorf("Duplicate environment entry for %s", key)
+				}
+				values[key] = value
+			}
+			for key, want := range tt.want {
+				if got, ok := values[key]; !ok || got != want {
+					t.Errorf("Expected %s=%q, got %q (present=%v)", key, want, got, ok)
+				}
+			}
+			for _, key := range tt.absent {
+				if _, ok := values[key]; ok {
+					t.Errorf("Expected %s to be absent", key)
+				}
+			}
+			if !slices.Equal(configured, tt.wantConfigured) {
+				t.Errorf("Expected configured keys %v, got %v", tt.wantConfigured, configured)
+			}
+		})
+	}
+}
+
+func TestBuildCommandEnv_Errors(t *testing.T) {
+	dir := t.TempDir()
+	badFile := filepath.Join(dir, "bad.env")
+	if err := os.WriteFile(badFile, []byte("GOOD=1\nsecret-value-without-key\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write env file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{"invalid_key", Config{Env: []string{"1BAD=value"}}, "not a valid variable name"},
+		{"bare_value", Config{Env: []string{"sk-secret"}}, "expected KEY=VALUE"},
+		{"unset_pass_through", Config{Env: []string{"MCP_DUMP_TEST_DEFINITELY_UNSET"}}, "not set in the current environment"},
+		{"bad_env_file_line", Config{EnvFiles: []string{badFile}}, "line 2"},
+		{"missing_env_file", Config{EnvFiles: []string{filepath.Join(dir, "missing.env")}}, "failed to read env file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := buildCommandEnv(&tt.config)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+			// Values must never leak into error messages
+			if strings.Contains(err.Error(), "secret") {
+				t.Errorf("Error message leaks a value: %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateCommandTransport_ProcessOptions(t *testing.T) {
+	dir := t.TempDir()
+
+	transport, err := createCommandTransport(&Config{
+		ServerCommand: "node server.js",
+		ClearEnv:      true,
+		Env:           []string{"API_KEY=secret"},
+		Cwd:           dir,
+	})
+	if err != nil {
+		t.Fatalf("createCommandTransport failed: %v", err)
+	}
+
+	cmd := transport.(*mcp.CommandTransport).Command
+	if cmd.Dir != dir {
+		t.Errorf("Expected working directory %s, got %s", dir, cmd.Dir)
+	}
+	if !slices.Equal(cmd.Env, []string{"API_KEY=secret"}) {
+		t.Errorf("Expected only the configured environment, got %v", cmd.Env)
+	}
+
+	if _, err := createCommandTransport(&Config{ServerCommand: "node server.js", Cwd: filepath.Join(dir, "missing")}); err == nil {
+		t.Error("Expected error for missing working directory")
+	}
+}
+
+func TestRedactEnv(t *testing.T) {
+	if got := redactEnv([]string{"API_KEY", "REGION"}); got != "API_KEY=***, REGION=***" {
+		t.Errorf("Unexpected redaction: %q", got)
+	}
+}
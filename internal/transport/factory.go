@@ -2,7 +2,10 @@ package transport
 
 import (
 	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
@@ -20,30 +23,75 @@ type Config struct {
 	Headers       []string
 	ServerCommand string
 	Args          []string
+
+	// Command transport process options
+	Env      []string  // KEY=VALUE entries (or bare KEY to pass through) added to the server environment
+	EnvFiles []string  // dotenv-style files applied before Env
+	ClearEnv bool      // start the server with only the configured variables
+	Cwd      string    // working directory for the server process
+	Stderr   io.Writer // receives the server's standard error (discarded when nil)
+
+	// Container options: run the server command inside a container instead of on the host
+	ContainerImage   string
+	ContainerRuntime string   // docker or podman; detected from PATH when empty
+	ContainerMounts  []string // host-path:container-path[:ro|rw]
+	ContainerNetwork string   // defaults to DefaultContainerNetwork
+
+	// TLS options for the HTTP-based transports
+	CACert             string // PEM file of additional CA certificates to trust
+	ClientCert         string // PEM client certificate for mutual TLS
+	ClientKey          string // PEM private key for ClientCert
+	TLSServerName      string // overrides the server name used for verification and SNI
+	InsecureSkipVerify bool   // disables certificate verification
+
+	// Network route for the HTTP-based transports
+	Proxy      string // proxy URL for all HTTP requests except hosts matched by NO_PROXY
+	UnixSocket string // Unix domain socket to connect to instead of the endpoint's host and port
+
+	// Retries for HTTP requests that fail with a network error or a retryable status
+	Retry RetryPolicy
 }
 
 // Create creates an MCP transport based on the configuration.
 // The oauthConfig parameter is optional and only used for HTTP-based transports.
 func Create(config *Config, oauthConfig *auth.Config) (mcp.Transport, error) {
-	switch config.Transport {
-	case "command":
+	if config.Transport == "command" {
 		return createCommandTransport(config)
+	}
+
+	base, err := NewHTTPTransport(config)
+	if err != nil {
+		return nil, err
+	}
+
+	switch config.Transport {
 	case "sse":
-		return createSSETransport(config, oauthConfig)
+		return createSSETransport(config, oauthConfig, base)
 	case "streamable":
-		return createStreamableTransport(config, oauthConfig)
+		return createStreamableTransport(config, oauthConfig, base)
+	case "websocket":
+		return createWebSocketTransport(config, oauthConfig, base)
+	case "auto":
+		return nil, fmt.Errorf("auto transport is selected while connecting; use ConnectAuto")
 	default:
 		return nil, fmt.Errorf("unknown transport type: %s", config.Transport)
 	}
 }
 
 func createCommandTransport(config *Config) (mcp.Transport, error) {
+	if config.ContainerImage != "" {
+		return createContainerTransport(config)
+	}
+
 	var cmd *exec.Cmd
 
 	switch {
 	case config.ServerCommand != "":
-		// Parse explicit server command
-		parts := strings.Fields(config.ServerCommand)
+		// Parse explicit server command using shell quoting rules
+		parts, err := splitShellWords(config.ServerCommand)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --server-command: %w", err)
+		}
 		if len(parts) == 0 {
 			return nil, fmt.Errorf("empty server command")
 		}
@@ -57,10 +105,52 @@ func createCommandTransport(config *Config) (mcp.Transport, error) {
 		return nil, fmt.Errorf("command transport requires command or args")
 	}
 
+	if err := configureCommandProcess(cmd, config); err != nil {
+		return nil, err
+	}
+
 	return &mcp.CommandTransport{Command: cmd}, nil
 }
 
-func createSSETransport(config *Config, oauthConfig *auth.Config) (mcp.Transport, error) {
+// configureCommandProcess applies the stderr, environment and working directory options to the server process.
+// Only variable names are logged; values are masked because they commonly hold API keys.
+func configureCommandProcess(cmd *exec.Cmd, config *Config) error {
+	if config.Stderr != nil {
+		cmd.Stderr = config.Stderr
+	}
+
+	if config.Cwd != "" {
+		info, err := os.Stat(config.Cwd)
+		if err != nil {
+			return fmt.Errorf("invalid --cwd: %w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("invalid --cwd: %s is not a directory", config.Cwd)
+		}
+		cmd.Dir = config.Cwd
+	}
+
+	if len(config.Env) == 0 && len(config.EnvFiles) == 0 && !config.ClearEnv {
+		// Inherit the environment unchanged
+		return nil
+	}
+
+	env, configured, err := buildCommandEnv(config)
+	if err != nil {
+		return err
+	}
+	cmd.Env = env
+
+	switch {
+	case config.ClearEnv:
+		log.Printf("Starting server with a cleared environment: %s", redactEnv(configured))
+	case len(configured) > 0:
+		log.Printf("Starting server with environment overrides: %s", redactEnv(configured))
+	}
+	return nil
+}
+
+func createSSETransport(config *Config, oauthConfig *auth.Config, base http.RoundTripper) (mcp.Transport, error) {
 	if config.Endpoint == "" {
 		return nil, fmt.Errorf("SSE transport requires --endpoint")
 	}
@@ -68,7 +158,7 @@ func createSSETransport(config *Config, oauthConfig *auth.Config) (mcp.Transport
 	httpClient := &http.Client{Timeout: config.Timeout}
 
 	// Build transport chain for SSE (OAuth layer added if configured)
-	transport, err := buildHTTPTransportChain(http.DefaultTransport, config.Headers, false, oauthConfig)
+	transport, err := buildHTTPTransportChain(withRetry(base, config.Retry), config.Headers, false, oauthConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build transport chain: %w", err)
 	}
@@ -80,7 +170,7 @@ func createSSETransport(config *Config, oauthConfig *auth.Config) (mcp.Transport
 	}, nil
 }
 
-func createStreamableTransport(config *Config, oauthConfig *auth.Config) (mcp.Transport, error) {
+func createStreamableTransport(config *Config, oauthConfig *auth.Config, base http.RoundTripper) (mcp.Transport, error) {
 	if config.Endpoint == "" {
 		return nil, fmt.Errorf("streamable transport requires --endpoint")
 	}
@@ -88,7 +178,7 @@ func createStreamableTransport(config *Config, oauthConfig *auth.Config) (mcp.Tr
 	httpClient := &http.Client{Timeout: config.Timeout}
 
 	// Build transport chain for streamable (includes content type fixing and OAuth if configured)
-	transport, err := buildHTTPTransportChain(http.DefaultTransport, config.Headers, true, oauthConfig)
+	transport, err := buildHTTPTransportChain(withRetry(base, config.Retry), config.Headers, true, oauthConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build transport chain: %w", err)
 	}
@@ -100,8 +190,28 @@ func createStreamableTransport(config *Config, oauthConfig *auth.Config) (mcp.Tr
 	}, nil
 }
 
+func createWebSocketTransport(config *Config, oauthConfig *auth.Config, base *http.Transport) (mcp.Transport, error) {
+	if config.Endpoint == "" {
+		return nil, fmt.Errorf("websocket transport requires --endpoint")
+	}
+
+	// Authenticate the upgrade request with the same chain as the HTTP transports. The
+	// timeout applies to the handshake only, since the connection stays open afterwards.
+	transport, err := buildHTTPTransportChain(withRetry(NewWebSocketBaseTransport(base), config.Retry), config.Headers, false, oauthConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build transport chain: %w", err)
+	}
+
+	return &WebSocketTransport{
+		Endpoint:         config.Endpoint,
+		HTTPClient:       &http.Client{Transport: transport},
+		HandshakeTimeout: config.Timeout,
+	}, nil
+}
+
 // buildHTTPTransportChain builds a chain of HTTP round trippers.
-// The chain order is: base → OAuth (if configured) → custom headers → content type fix
+// The chain order is: base → OAuth (if configured) → custom headers → content type fix.
+// Callers pass a base wrapped with retries, so every attempt carries the same token and headers.
 func buildHTTPTransportChain(base http.RoundTripper, headerStrings []string, includeContentTypeFix bool, oauthConfig *auth.Config) (http.RoundTripper, error) {
 	transport := base
 
@@ -0,0 +1,110 @@
+package transport
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestSplitShellWords(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr string
+	}{
+		{name: "empty", input: "", want: nil},
+		{name: "whitespace_only", input: " \t\n ", want: nil},
+		{name: "simple", input: "python server.py --arg value", want: []string{"python", "server.py", "--arg", "value"}},
+		{name: "repeated_whitespace", input: "  node\t\tserver.js  ", want: []string{"node", "server.js"}},
+		{name: "double_quoted_path", input: `node "/Users/me/My Docs/server.js"`, want: []string{"node", "/Users/me/My Docs/server.js"}},
+		{name: "single_quoted_json", input: `server --config '{"debug": true, "name": "x"}'`, want: []string{"server", "--config", `{"debug": true, "name": "x"}`}},
+		{name: "adjacent_quotes_join", input: `--name="my server"'s'`, want: []string{"--name=my servers"}},
+		{name: "empty_quoted_arguments", input: `cmd "" ''`, want: []string{"cmd", "", ""}},
+		{name: "backslash_escapes_space", input: `node My\ Docs/server.js`, want: []string{"node", "My Docs/server.js"}},
+		{name: "backslash_escapes_quote", input: `echo \"hi\"`, want: []string{"echo", `"hi"`}},
+		{name: "double_quote_escapes", input: `echo "a \"b\" \\ \$HOME \x"`, want: []string{"echo", `a "b" \ $HOME \x`}},
+		{name: "single_quotes_keep_backslashes", input: `echo 'a\nb'`, want: []string{"echo", `a\nb`}},
+		{name: "line_continuation", input: "node \\\nserver.js", want: []string{"node", "server.js"}},
+		{name: "no_expansion", input: `ls $HOME *.go ~ $(whoami)`, want: []string{"ls", "$HOME", "*.go", "~", "$(whoami)"}},
+		{name: "unicode", input: `echo "héllo wörld"`, want: []string{"echo", "héllo wörld"}},
+		{name: "unterminated_single_quote", input: `node 'server.js`, wantErr: "unterminated single quote starting at position 6"},
+		{name: "unterminated_double_quote", input: `node "server.js`, wantErr: "unterminated double quote starting at position 6"},
+		{name: "escaped_closing_quote", input: `node "server.js\"`, wantErr: "unterminated double quote"},
+		{name: "trailing_backslash", input: `node server.js\`, wantErr: "trailing backslash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitShellWords(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v (words %q)", tt.wantErr, err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("splitShellWords(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateCommandTransport_ServerCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		wantArgs []string
+		wantErr  string
+	}{
+		{
+			name:     "quoted_server_command",
+			config:   Config{ServerCommand: `python "/opt/my server/main.py" --flag='a b'`},
+			wantArgs: []string{"python", "/opt/my server/main.py", "--flag=a b"},
+		},
+		{
+			name:     "legacy_args",
+			config:   Config{Args: []string{"node", "server with spaces.js"}},
+			wantArgs: []string{"node", "server with spaces.js"},
+		},
+		{
+			name:    "unterminated_quote",
+			config:  Config{ServerCommand: `python "main.py`},
+			wantErr: "invalid --server-command: unterminated double quote",
+		},
+		{
+			name:    "empty_server_command",
+			config:  Config{ServerCommand: `  `},
+			wantErr: "empty server command",
+		},
+		{
+			name:    "no_command",
+			config:  Config{},
+			wantErr: "requires command or args",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, err := createCommandTransport(&tt.config)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			cmd := transport.(*mcp.CommandTransport).Command
+			if !slices.Equal(cmd.Args, tt.wantArgs) {
+				t.Errorf("Expected args %q, got %q", tt.wantArgs, cmd.Args)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// NewHTTPTransport returns the base HTTP transport for the HTTP-based transports, configured
+// with the TLS, proxy and Unix socket options in config. It is also used for OAuth discovery,
+// registration and token requests so they take the same route and trust the same CAs as MCP
+// requests.
+func NewHTTPTransport(config *Config) (*http.Transport, error) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+
+	tlsConfig, err := buildTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		base.TLSClientConfig = tlsConfig
+	}
+
+	if config.Proxy != "" && config.UnixSocket != "" {
+		return nil, errors.New("--proxy and --unix-socket cannot be used together")
+	}
+
+	if config.Proxy != "" {
+		proxy, err := proxyFunc(config.Proxy, noProxyFromEnvironment())
+		if err != nil {
+			return nil, err
+		}
+		base.Proxy = proxy
+	}
+
+	if config.UnixSocket != "" {
+		socketAddr, err := unixSocketAddr(config.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		base.DialContext = unixSocketDialer(config.UnixSocket, socketAddr, base.DialContext)
+		base.Proxy = bypassProxyFor(socketAddr, base.Proxy)
+	}
+
+	return base, nil
+}
+
+// parseProxyURL parses a --proxy value, defaulting to http:// when no scheme is given
+func parseProxyURL(proxy string) (*url.URL, error) {
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --proxy: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid --proxy %s: scheme must be http, https or socks5", u.Redacted())
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid --proxy %s: missing host", u.Redacted())
+	}
+	return u, nil
+}
+
+// proxyFunc sends every request through proxy, except those to hosts matched by noProxy.
+// Like http.ProxyFromEnvironment, requests to localhost and loopback addresses are never proxied.
+func proxyFunc(proxy, noProxy string) (func(*http.Request) (*url.URL, error), error) {
+	proxyURL, err := parseProxyURL(proxy)
+	if err != nil {
+		return nil, err
+	}
+	bypass := parseNoProxy(noProxy)
+	return func(req *http.Request) (*url.URL, error) {
+		if isLoopback(req.URL.Hostname()) || bypass.matches(req.URL) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+// isLoopback reports whether host is localhost or a loopback IP address
+func isLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// noProxyFromEnvironment returns NO_PROXY, or no_proxy if it is unset
+func noProxyFromEnvironment() string {
+	if value, ok := os.LookupEnv("NO_PROXY"); ok {
+		return value
+	}
+	return os.Getenv("no_proxy")
+}
+
+// noProxyRule is one NO_PROXY entry
+type noProxyRule struct {
+	domain string     // matches the host and its subdomains ("example.com", ".example.com")
+	port   string     // restricts the rule to a port when set
+	ipNet  *net.IPNet // matches IP addresses in a CIDR range
+	ip     net.IP     // matches a single IP address
+}
+
+// noProxyRules holds the parsed NO_PROXY entries
+type noProxyRules struct {
+	all   bool // "*" bypasses the proxy for every host
+	rules []noProxyRule
+}
+
+// parseNoProxy parses a comma-separated NO_PROXY value. Entries are host names (matching
+// subdomains too, with or without a leading dot), IP addresses, CIDR ranges, optionally
+// followed by :port, or "*" to disable the proxy.
+func parseNoProxy(value string) *noProxyRules {
+	rules := &noProxyRules{}
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case entry == "*":
+			rules.all = true
+			continue
+		}
+
+		if _, ipNet, err := net.ParseCIDR(entry); err == nil {
+			rules.rules = append(rules.rules, noProxyRule{ipNet: ipNet})
+			continue
+		}
+
+		host, port := entry, ""
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			host, port = h, p
+		}
+		if ip := net.ParseIP(host); ip != nil {
+			rules.rules = append(rules.rules, noProxyRule{ip: ip, port: port})
+			continue
+		}
+		rules.rules = append(rules.rules, noProxyRule{domain: strings.TrimPrefix(host, "."), port: port})
+	}
+	return rules
+}
+
+// matches reports whether requests to u should bypass the proxy
+func (r *noProxyRules) matches(u *url.URL) bool {
+	if r.all {
+		return true
+	}
+
+	host := strings.ToLower(u.Hostname())
+	port := urlPort(u)
+	ip := net.ParseIP(host)
+
+	for _, rule := range r.rules {
+		if rule.port != "" && rule.port != port {
+			continue
+		}
+		switch {
+		case rule.ipNet != nil:
+			if ip != nil && rule.ipNet.Contains(ip) {
+				return true
+			}
+		case rule.ip != nil:
+			if ip != nil && rule.ip.Equal(ip) {
+				return true
+			}
+		case host == rule.domain || strings.HasSuffix(host, "."+rule.domain):
+			return true
+		}
+	}
+	return false
+}
+
+// urlPort returns the port of u, or the default port for its scheme
+func urlPort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	if u.Scheme == "https" || u.Scheme == "wss" {
+		return "443"
+	}
+	return "80"
+}
+
+// unixSocketAddr returns the host:port of the endpoint, whose connections go to the Unix socket
+func unixSocketAddr(endpoint string) (string, error) {
+	if endpoint == "" {
+		return "", errors.New("--unix-socket requires --endpoint (for example http://localhost/mcp)")
+	}
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil || endpointURL.Host == "" {
+		return "", fmt.Errorf("invalid --endpoint %q for --unix-socket", endpoint)
+	}
+	return net.JoinHostPort(endpointURL.Hostname(), urlPort(endpointURL)), nil
+}
+
+// unixSocketDialer dials socketPath for connections to socketAddr and uses dial for any other
+// address, so OAuth requests to an external authorization server still go out over TCP
+func unixSocketDialer(socketPath, socketAddr string, dial func(context.Context, string, string) (net.Conn, error)) func(context.Context, string, string) (net.Conn, error) {
+	var dialer net.Dialer
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if addr != socketAddr {
+			return dial(ctx, network, addr)
+		}
+		return dialer.DialContext(ctx, "unix", socketPath)
+	}
+}
+
+// bypassProxyFor skips the proxy for requests to addr and defers to proxy for the rest
+func bypassProxyFor(addr string, proxy func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		if proxy == nil || net.JoinHostPort(req.URL.Hostname(), urlPort(req.URL)) == addr {
+			return nil, nil
+		}
+		return proxy(req)
+	}
+}
@@ -0,0 +1,255 @@
+package transport
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/spandigital/mcp-server-dump/internal/auth"
+)
+
+func TestNoProxyRules(t *testing.T) {
+	rules := parseNoProxy(" internal.example.com, .corp.test ,10.0.0.0/8, 192.168.1.5, api.example.org:8443,")
+
+	tests := []struct {
+		url    string
+		bypass bool
+	}{
+		{"https://internal.example.com/mcp", true},
+		{"https://mcp.internal.example.com/mcp", true},
+		{"https://notinternal.example.com/mcp", false},
+		{"http://corp.test/mcp", true},
+		{"http://a.b.corp.test/mcp", true},
+		{"http://10.1.2.3:8080/mcp", true},
+		{"http://11.1.2.3/mcp", false},
+		{"http://192.168.1.5/mcp", true},
+		{"http://192.168.1.6/mcp", false},
+		{"https://api.example.org:8443/mcp", true},
+		{"https://api.example.org/mcp", false},
+		{"https://example.com/mcp", false},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatalf("Invalid test URL %s: %v", tt.url, err)
+		}
+		if got := rules.matches(u); got != tt.bypass {
+			t.Errorf("matches(%s) = %v, want %v", tt.url, got, tt.bypass)
+		}
+	}
+
+	all := parseNoProxy("*")
+	if !all.matches(&url.URL{Scheme: "https", Host: "anything.example"}) {
+		t.Error("Expected * to bypass the proxy for every host")
+	}
+}
+
+func TestProxyFunc(t *testing.T) {
+	proxy, err := proxyFunc("proxy.corp.test:3128", "direct.example.com")
+	if err != nil {
+		t.Fatalf("proxyFunc failed: %v", err)
+	}
+
+	tests := map[string]string{
+		"https://mcp.example.com/mcp":    "http://proxy.corp.test:3128",
+		"https://direct.example.com/mcp": "",
+		"http://localhost:3001/mcp":      "",
+		"http://127.0.0.1:3001/mcp":      "",
+		"http://[::1]:3001/mcp":          "",
+	}
+	for target, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, target, http.NoBody)
+		got, err := proxy(req)
+		if err != nil {
+			t.Fatalf("proxy(%s) failed: %v", target, err)
+		}
+		if (got == nil && want != "") || (got != nil && got.String() != want) {
+			t.Errorf("proxy(%s) = %v, want %q", target, got, want)
+		}
+	}
+}
+
+func TestParseProxyURL(t *testing.T) {
+	tests := []struct {
+		proxy   string
+		want    string
+		wantErr string
+	}{
+		{proxy: "proxy.corp.test:3128", want: "http://proxy.corp.test:3128"},
+		{proxy: "https://proxy.corp.test", want: "https://proxy.corp.test"},
+		{proxy: "socks5://127.0.0.1:1080", want: "socks5://127.0.0.1:1080"},
+		{proxy: "ftp://proxy.corp.test", wantErr: "scheme must be"},
+		{proxy: "http://user:secret@", wantErr: "missing host"},
+	}
+	for _, tt := range tests {
+		got, err := parseProxyURL(tt.proxy)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseProxyURL(%q) error = %v, want %q", tt.proxy, err, tt.wantErr)
+			}
+			if err != nil && strings.Contains(err.Error(), "secret") {
+				t.Errorf("Expected proxy password to be redacted, got %v", err)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Errorf("parseProxyURL(%q) = %v, %v; want %s", tt.proxy, got, err, tt.want)
+		}
+	}
+}
+
+func TestCreate_StreamableThroughProxy(t *testing.T) {
+	t.Setenv("NO_PROXY", "")
+
+	// The proxy serves the MCP endpoint itself, recording the hosts it was asked to reach
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "proxied-server", Version: "1.0.0"}, nil)
+	// Localhost protection would reject the proxied Host header on the loopback listener
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return mcpServer },
+		&mcp.StreamableHTTPOptions{DisableLocalhostProtection: true})
+	var mu sync.Mutex
+	var hosts []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hosts = append(hosts, r.URL.Host)
+		mu.Unlock()
+		handler.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	mcpTransport, err := Create(&Config{
+		Transport: "streamable",
+		Endpoint:  "http://mcp.corp.test/mcp",
+		Timeout:   5 * time.Second,
+		Proxy:     proxy.URL,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(context.Background(), mcpTransport, nil)
+	if err != nil {
+		t.Fatalf("Connect through proxy failed: %v", err)
+	}
+	_ = session.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(hosts) == 0 || hosts[0] != "mcp.corp.test" {
+		t.Errorf("Expected proxied requests for mcp.corp.test, got %v", hosts)
+	}
+}
+
+func TestNewHTTPTransport_NoProxyFromEnvironment(t *testing.T) {
+	t.Setenv("NO_PROXY", "mcp.corp.test")
+
+	base, err := NewHTTPTransport(&Config{Proxy: "http://proxy.corp.test:3128"})
+	if err != nil {
+		t.Fatalf("NewHTTPTransport failed: %v", err)
+	}
+
+	for target, proxied := range map[string]bool{
+		"http://mcp.corp.test/mcp":   false,
+		"http://other.corp.test/mcp": true,
+	} {
+		got, err := base.Proxy(httptest.NewRequest(http.MethodGet, target, http.NoBody))
+		if err != nil {
+			t.Fatalf("Proxy(%s) failed: %v", target, err)
+		}
+		if (got != nil) != proxied {
+			t.Errorf("Proxy(%s) = %v, want proxied=%v", target, got, proxied)
+		}
+	}
+}
+
+func TestNewHTTPTransport_UnixSocket(t *testing.T) {
+	// Socket paths are limited to ~100 bytes, so avoid the long per-test temp directory
+	dir, err := os.MkdirTemp("", "mcp-sock")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	socketPath := filepath.Join(dir, "gateway.sock")
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("Failed to listen on Unix socket: %v", err)
+	}
+
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "socket-server", Version: "1.0.0"}, nil)
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return mcpServer }, nil))
+	mux.HandleFunc("/protected", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	mux.HandleFunc("/.well-known/oauth-authorization-server", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"issuer":"http://gateway.local","token_endpoint":"http://gateway.local/token"}`))
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	config := &Config{
+		Transport:  "streamable",
+		Endpoint:   "http://gateway.local/mcp",
+		Timeout:    5 * time.Second,
+		UnixSocket: socketPath,
+	}
+	mcpTransport, err := Create(config, nil)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(context.Background(), mcpTransport, nil)
+	if err != nil {
+		t.Fatalf("Connect over Unix socket failed: %v", err)
+	}
+	if name := session.InitializeResult().ServerInfo.Name; name != "socket-server" {
+		t.Errorf("Expected server name socket-server, got %q", name)
+	}
+	_ = session.Close()
+
+	// OAuth discovery takes the same route when given the base transport
+	base, err := NewHTTPTransport(config)
+	if err != nil {
+		t.Fatalf("NewHTTPTransport failed: %v", err)
+	}
+	discovered, err := auth.DiscoverAndConfigure(auth.WithHTTPTransport(context.Background(), base), "http://gateway.local/protected")
+	if err != nil {
+		t.Fatalf("Discovery over Unix socket failed: %v", err)
+	}
+	if discovered == nil || discovered.TokenURL != "http://gateway.local/token" {
+		t.Errorf("Unexpected discovered config: %+v", discovered)
+	}
+}
+
+func TestNewHTTPTransport_InvalidRoute(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{"proxy and socket", Config{Endpoint: "http://localhost/mcp", Proxy: "proxy:3128", UnixSocket: "/tmp/mcp.sock"}, "cannot be used together"},
+		{"socket without endpoint", Config{UnixSocket: "/tmp/mcp.sock"}, "requires --endpoint"},
+		{"invalid proxy", Config{Proxy: "ftp://proxy"}, "scheme must be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewHTTPTransport(&tt.config); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Default retry settings
+const (
+	// DefaultRetryAttempts is the default number of attempts, including the first one
+	DefaultRetryAttempts = 3
+	// DefaultRetryBackoff is the default delay before the first retry
+	DefaultRetryBackoff = 500 * time.Millisecond
+	// DefaultRetryMaxBackoff is the default upper bound on the delay between attempts
+	DefaultRetryMaxBackoff = 10 * time.Second
+)
+
+// DefaultRetryableStatus lists the HTTP status codes retried by default: rate limiting and
+// the gateway errors load balancers return while a backend restarts. 504 is not among them,
+// as the backend may still have processed the request.
+var DefaultRetryableStatus = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+}
+
+// sideEffectMethods lists the JSON-RPC methods whose requests are never replayed, because the
+// server may have acted on them even when the response reports a failure
+var sideEffectMethods = []string{"tools/call"}
+
+// idempotentMethods lists the HTTP methods that are safe to repeat after the request was sent
+var idempotentMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodDelete}
+
+// RetryPolicy controls how transient failures are retried
+type RetryPolicy struct {
+	MaxAttempts     int           // total attempts including the first; 1 or less disables retries
+	InitialBackoff  time.Duration // delay before the first retry, doubled for each further retry
+	MaxBackoff      time.Duration // upper bound on the delay between attempts
+	RetryableStatus []int         // HTTP status codes that are retried
+}
+
+// DefaultRetryPolicy returns the retry policy used when no options are given
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:     DefaultRetryAttempts,
+		InitialBackoff:  DefaultRetryBackoff,
+		MaxBackoff:      DefaultRetryMaxBackoff,
+		RetryableStatus: DefaultRetryableStatus,
+	}
+}
+
+// Enabled reports whether the policy allows more than one attempt
+func (p RetryPolicy) Enabled() bool {
+	return p.MaxAttempts > 1
+}
+
+// Backoff returns the delay after the given failed attempt (starting at 1). The delay doubles
+// with each attempt up to MaxBackoff, and half of it is random so clients that failed together
+// do not retry together.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || delay < p.MaxBackoff); i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	// #nosec G404 - jitter does not need a cryptographically secure source
+	return half + rand.N(delay-half+1)
+}
+
+// Do calls fn until it succeeds, returns an error that retryable rejects, or the attempts
+// run out, logging each retry of operation with its attempt count
+func (p RetryPolicy) Do(ctx context.Context, operation string, retryable func(error) bool, fn func() error) error {
+	attempts := max(p.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			if attempt > 1 {
+				log.Printf("%s succeeded on attempt %d/%d", operation, attempt, attempts)
+			}
+			return nil
+		}
+		if attempt >= attempts || ctx.Err() != nil || !retryable(err) {
+			if attempt > 1 {
+				return fmt.Errorf("%w (after %d attempts)", err, attempt)
+			}
+			return err
+		}
+
+		delay := p.Backoff(attempt)
+		log.Printf("Warning: %s failed (attempt %d/%d), retrying in %v: %v", operation, attempt, attempts, delay.Round(time.Millisecond), err)
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// retryableStatus reports whether the policy retries responses with the given status
+func (p RetryPolicy) retryableStatus(status int) bool {
+	return slices.Contains(p.RetryableStatus, status)
+}
+
+// RetryRoundTripper retries requests that fail with a transient network error or a retryable
+// status. Requests are replayed with Request.GetBody, so requests whose body cannot be replayed
+// are sent once, as are JSON-RPC requests with side effects such as tools/call. A non-idempotent
+// request that failed after it was sent is not replayed, since the server may have received it.
+type RetryRoundTripper struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+}
+
+// NewRetryRoundTripper creates a round tripper that retries requests on base according to policy
+func NewRetryRoundTripper(base http.RoundTripper, policy RetryPolicy) *RetryRoundTripper {
+	return &RetryRoundTripper{base: base, policy: policy}
+}
+
+// withRetry wraps base in a RetryRoundTripper when policy allows retries
+func withRetry(base http.RoundTripper, policy RetryPolicy) http.RoundTripper {
+	if !policy.Enabled() {
+		return base
+	}
+	return NewRetryRoundTripper(base, policy)
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RetryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	if !replayable || !t.policy.Enabled() || hasSideEffects(req) {
+		return t.base.RoundTrip(req)
+	}
+
+	attemptReq := req
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(attemptReq)
+
+		reason, retry := t.shouldRetry(req, resp, err)
+		if !retry || attempt >= t.policy.MaxAttempts {
+			if attempt > 1 && retry {
+				log.Printf("Warning: %s %s still failing after %d attempts (%s)", req.Method, req.URL.Redacted(), attempt, reason)
+			}
+			return resp, err
+		}
+
+		delay := t.policy.Backoff(attempt)
+		if after, ok := retryAfter(resp); ok {
+			delay = after
+			if t.policy.MaxBackoff > 0 && delay > t.policy.MaxBackoff {
+				delay = t.policy.MaxBackoff
+			}
+		}
+		if resp != nil {
+			// Drain the body so the connection can be reused
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			_ = resp.Body.Close()
+		}
+
+		log.Printf("Retrying %s %s after %s (attempt %d/%d in %v)", req.Method, req.URL.Redacted(), reason,
+			attempt+1, t.policy.MaxAttempts, delay.Round(time.Millisecond))
+		if err := sleepContext(req.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		attemptReq, err = rewindRequest(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// shouldRetry reports whether an attempt failed in a way worth retrying, and why
+func (t *RetryRoundTripper) shouldRetry(req *http.Request, resp *http.Response, err error) (string, bool) {
+	if err != nil {
+		// Cancellation and client timeouts end the request for good
+		if req.Context().Err() != nil || permanentError(err) {
+			return "", false
+		}
+		// A request that may have reached the server is only repeated if that is harmless
+		if !dialError(err) && !slices.Contains(idempotentMethods, req.Method) {
+			return "", false
+		}
+		return err.Error(), true
+	}
+	if t.policy.retryableStatus(resp.StatusCode) {
+		return resp.Status, true
+	}
+	return "", false
+}
+
+// hasSideEffects reports whether req carries a JSON-RPC request whose method has side effects
+func hasSideEffects(req *http.Request) bool {
+	if req.GetBody == nil {
+		return false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return true // Cannot tell, so do not replay
+	}
+	defer func() { _ = body.Close() }()
+
+	var msg struct {
+		Method string `json:"method"`
+	}
+	if err := json.NewDecoder(body).Decode(&msg); err != nil {
+		return false
+	}
+	return slices.Contains(sideEffectMethods, msg.Method)
+}
+
+// dialError reports whether err occurred while connecting to the server or proxy, before any
+// part of the request was sent
+func dialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && (opErr.Op == "dial" || opErr.Op == "proxyconnect")
+}
+
+// permanentError reports whether err is a network failure that retrying cannot fix: a
+// certificate that does not verify, a TLS handshake the peer rejected, a host name that does
+// not resolve, or a Unix socket that is missing or inaccessible
+func permanentError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsNotFound
+	}
+
+	var (
+		unknownAuthority x509.UnknownAuthorityError
+		invalidCert      x509.CertificateInvalidError
+		hostnameErr      x509.HostnameError
+		verifyErr        *tls.CertificateVerificationError
+		recordErr        tls.RecordHeaderError
+		opErr            *net.OpError
+	)
+	switch {
+	case errors.As(err, &unknownAuthority), errors.As(err, &invalidCert), errors.As(err, &hostnameErr),
+		errors.As(err, &verifyErr), errors.As(err, &recordErr):
+		return true
+	case errors.As(err, &opErr) && opErr.Op == "remote error":
+		return true // A TLS alert from the peer, such as a rejected client certificate
+	}
+	return errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission)
+}
+
+// rewindRequest returns a copy of req with a fresh body for the next attempt
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		next.Body = body
+	}
+	return next, nil
+}
+
+// retryAfter returns the delay requested by a Retry-After header, in seconds or as an HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// sleepContext waits for d, returning early with the context's error if it is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
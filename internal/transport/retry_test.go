@@ -0,0 +1,338 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// testRetryPolicy retries quickly so tests do not wait on real backoff delays
+func testRetryPolicy(attempts int) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:     attempts,
+		InitialBackoff:  time.Millisecond,
+		MaxBackoff:      5 * time.Millisecond,
+		RetryableStatus: DefaultRetryableStatus,
+	}
+}
+
+// flakyHandler fails the first failures requests with status, then defers to next
+func flakyHandler(failures int32, status int, next http.Handler) (http.Handler, *atomic.Int32) {
+	var calls atomic.Int32
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	}), &calls
+}
+
+func TestRetryRoundTripper(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   int32
+		status     int
+		attempts   int
+		wantStatus int
+		wantCalls  int32
+	}{
+		{"recovers after bad gateway", 2, http.StatusBadGateway, 3, http.StatusOK, 3},
+		{"gives up after max attempts", 5, http.StatusServiceUnavailable, 3, http.StatusServiceUnavailable, 3},
+		{"does not retry other statuses", 1, http.StatusInternalServerError, 3, http.StatusInternalServerError, 1},
+		{"disabled with one attempt", 1, http.StatusBadGateway, 1, http.StatusBadGateway, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bodies []string
+			handler, calls := flakyHandler(tt.failures, tt.status, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+				w.WriteHeader(http.StatusOK)
+			}))
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			client := &http.Client{Transport: NewRetryRoundTripper(http.DefaultTransport, testRetryPolicy(tt.attempts))}
+			resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"jsonrpc":"2.0"}`))
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("Expected %d requests, got %d", tt.wantCalls, got)
+			}
+			// The body is replayed in full on the attempt that succeeds
+			for _, body := range bodies {
+				if body != `{"jsonrpc":"2.0"}` {
+					t.Errorf("Expected replayed request body, got %q", body)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryRoundTripper_NetworkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var calls atomic.Int32
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if calls.Add(1) == 1 {
+			return nil, errors.New("connection reset by peer")
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	client := &http.Client{Transport: NewRetryRoundTripper(base, testRetryPolicy(3))}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	_ = resp.Body.Close()
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestRetryRoundTripper_DoesNotReplayToolCalls(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		status    int
+		wantCalls int32
+	}{
+		{"tool call after gateway timeout", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"deploy"}}`, http.StatusGatewayTimeout, 1},
+		{"tool call after bad gateway", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"deploy"}}`, http.StatusBadGateway, 1},
+		{"list after bad gateway", `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`, http.StatusBadGateway, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, calls := flakyHandler(1, tt.status, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			// Even a policy that retries 504 does not replay a tool call
+			policy := testRetryPolicy(3)
+			policy.RetryableStatus = append([]int{http.StatusGatewayTimeout}, DefaultRetryableStatus...)
+			client := &http.Client{Transport: NewRetryRoundTripper(http.DefaultTransport, policy)}
+			resp, err := client.Post(server.URL, "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("Expected %d requests, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestRetryRoundTripper_ErrorClassification(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	resetErr := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+
+	tests := []struct {
+		name      string
+		method    string
+		err       error
+		wantCalls int32
+	}{
+		{"dial failure", http.MethodPost, dialErr, 2},
+		{"reset after sending a post", http.MethodPost, resetErr, 1},
+		{"reset after sending a get", http.MethodGet, resetErr, 2},
+		{"unknown certificate authority", http.MethodGet, &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}, 1},
+		{"rejected client certificate", http.MethodGet, &net.OpError{Op: "remote error", Err: errors.New("tls: bad certificate")}, 1},
+		{"host not found", http.MethodGet, &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "mcp.invalid", IsNotFound: true}}, 1},
+		{"temporary dns failure", http.MethodGet, &net.OpError{Op: "dial", Err: &net.DNSError{Err: "server misbehaving", Name: "mcp.example.com", IsTemporary: true}}, 2},
+		{"missing unix socket", http.MethodPost, &net.OpError{Op: "dial", Net: "unix", Err: os.NewSyscallError("connect", syscall.ENOENT)}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			base := roundTripperFunc(func(*http.Request) (*http.Response, error) {
+				if calls.Add(1) == 1 {
+					return nil, tt.err
+				}
+				return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+			})
+
+			req, _ := http.NewRequest(tt.method, "http://mcp.example.com/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+			resp, err := NewRetryRoundTripper(base, testRetryPolicy(3)).RoundTrip(req)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("Expected %d attempts, got %d (err %v)", tt.wantCalls, got, err)
+			}
+		})
+	}
+}
+
+func TestRetryRoundTripper_ContextCancelled(t *testing.T) {
+	handler, calls := flakyHandler(10, http.StatusBadGateway, http.NotFoundHandler())
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// A long backoff is cut short by the request context
+	policy := testRetryPolicy(5)
+	policy.InitialBackoff, policy.MaxBackoff = time.Minute, time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, http.NoBody)
+	start := time.Now()
+	resp, err := NewRetryRoundTripper(http.DefaultTransport, policy).RoundTrip(req)
+	if err == nil {
+		_ = resp.Body.Close()
+		t.Fatal("Expected the cancelled context to end the retries")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("Retry did not stop when the context was cancelled")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 attempt, got %d", calls.Load())
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"soon", 0, false},
+		{time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, true},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{Header: http.Header{}}
+		if tt.value != "" {
+			resp.Header.Set("Retry-After", tt.value)
+		}
+		got, ok := retryAfter(resp)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("retryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 200 * time.Millisecond, 400 * time.Millisecond},
+		{10, 500 * time.Millisecond, time.Second},
+	}
+	for _, tt := range tests {
+		for range 20 {
+			if got := policy.Backoff(tt.attempt); got < tt.min || got > tt.max {
+				t.Fatalf("Backoff(%d) = %v, want between %v and %v", tt.attempt, got, tt.min, tt.max)
+			}
+		}
+	}
+}
+
+func TestRetryPolicy_Do(t *testing.T) {
+	transient := errors.New("transient")
+	final := errors.New("final")
+
+	calls := 0
+	err := testRetryPolicy(3).Do(context.Background(), "test", func(err error) bool { return err == transient }, func() error {
+		calls++
+		if calls < 3 {
+			return transient
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("Expected success on the third attempt, got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	err = testRetryPolicy(3).Do(context.Background(), "test", func(err error) bool { return err == transient }, func() error {
+		calls++
+		return final
+	})
+	if !errors.Is(err, final) || calls != 1 {
+		t.Errorf("Expected a non-retryable error to stop after 1 call, got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	err = testRetryPolicy(2).Do(context.Background(), "test", func(error) bool { return true }, func() error {
+		calls++
+		return transient
+	})
+	if !errors.Is(err, transient) || !strings.Contains(err.Error(), "after 2 attempts") || calls != 2 {
+		t.Errorf("Expected failure after 2 attempts, got %v after %d calls", err, calls)
+	}
+}
+
+func TestCreate_StreamableRetriesBadGateway(t *testing.T) {
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "flaky-server", Version: "1.0.0"}, nil)
+	handler, calls := flakyHandler(1, http.StatusBadGateway,
+		mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return mcpServer }, nil))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	mcpTransport, err := Create(&Config{
+		Transport: "streamable",
+		Endpoint:  server.URL,
+		Timeout:   5 * time.Second,
+		Retry:     testRetryPolicy(3),
+	}, nil)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(context.Background(), mcpTransport, nil)
+	if err != nil {
+		t.Fatalf("Connect failed despite retries: %v", err)
+	}
+	defer func() { _ = session.Close() }()
+
+	if _, err := session.ListTools(context.Background(), nil); err != nil {
+		t.Errorf("ListTools failed: %v", err)
+	}
+	if calls.Load() < 2 {
+		t.Errorf("Expected the bad gateway response to be retried, got %d requests", calls.Load())
+	}
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,104 @@
+package transport
+
+import (
+	"fmt"
+	"strings"
+)
+
+// splitShellWords splits a command line into words following POSIX shell quoting rules:
+// whitespace separates words, single quotes preserve everything literally, double quotes
+// allow \" \\ \$ and \` escapes, and a backslash outside quotes escapes the next character.
+// No globbing, variable expansion or command substitution is performed, so characters such
+// as $, * and ~ are passed through unchanged.
+func splitShellWords(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+
+		case r == '\\':
+			if i+1 >= len(runes) {
+				return nil, fmt.Errorf("trailing backslash at position %d", i+1)
+			}
+			i++
+			// A backslash-newline is a line continuation and produces nothing
+			if runes[i] != '\n' {
+				word.WriteRune(runes[i])
+				inWord = true
+			}
+
+		case r == '\'':
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote starting at position %d", i+1)
+			}
+			word.WriteString(string(runes[i+1 : end]))
+			inWord = true
+			i = end
+
+		case r == '"':
+			end, err := readDoubleQuoted(runes, i, &word)
+			if err != nil {
+				return nil, err
+			}
+			inWord = true
+			i = end
+
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// readDoubleQuoted appends the contents of the double-quoted string starting at runes[start]
+// to word and returns the index of the closing quote
+func readDoubleQuoted(runes []rune, start int, word *strings.Builder) (int, error) {
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '"':
+			return i, nil
+		case '\\':
+			if i+1 < len(runes) {
+				switch next := runes[i+1]; next {
+				case '"', '\\', '$', '`':
+					word.WriteRune(next)
+					i++
+					continue
+				case '\n':
+					i++
+					continue
+				}
+			}
+			word.WriteRune('\\')
+		default:
+			word.WriteRune(runes[i])
+		}
+	}
+	return 0, fmt.Errorf("unterminated double quote starting at position %d", start+1)
+}
+
+// indexRune returns the index of the first r in runes at or after start, or -1
+func indexRune(runes []rune, start int, r rune) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,117 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+)
+
+// DefaultStderrBufferSize is how much of the server's most recent stderr output is kept
+const DefaultStderrBufferSize = 64 * 1024
+
+// StderrBuffer is a bounded ring buffer that keeps the most recent output written to it.
+// It is safe for concurrent use, so it can be read while the server is still writing.
+type StderrBuffer struct {
+	mu      sync.Mutex
+	data    []byte
+	size    int
+	dropped bool
+}
+
+// NewStderrBuffer creates a buffer that keeps at most size bytes
+func NewStderrBuffer(size int) *StderrBuffer {
+	if size <= 0 {
+		size = DefaultStderrBufferSize
+	}
+	return &StderrBuffer{size: size}
+}
+
+// Write implements io.Writer, discarding the oldest output once the buffer is full
+func (b *StderrBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(p) >= b.size {
+		b.data = append(b.data[:0], p[len(p)-b.size:]...)
+		b.dropped = true
+		return len(p), nil
+	}
+	if overflow := len(b.data) + len(p) - b.size; overflow > 0 {
+		b.data = append(b.data[:0], b.data[overflow:]...)
+		b.dropped = true
+	}
+	b.data = append(b.data, p...)
+	return len(p), nil
+}
+
+// Tail returns up to maxLines of the most recent complete or partial lines, without a
+// trailing newline. A line cut off by the ring buffer wrapping is dropped.
+func (b *StderrBuffer) Tail(maxLines int) string {
+	b.mu.Lock()
+	data := bytes.Clone(b.data)
+	dropped := b.dropped
+	b.mu.Unlock()
+
+	if dropped {
+		// The first line is probably incomplete after the buffer wrapped
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\r\n"), "\n")
+	if len(lines) > maxLines {
+		lines = lines[len(lines)-maxLines:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PrefixWriter writes complete lines to an underlying writer, each preceded by a prefix.
+// Partial lines are held until their newline arrives or Flush is called.
+type PrefixWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	prefix  string
+	partial []byte
+}
+
+// NewPrefixWriter returns a writer that prefixes every line written to w
+func NewPrefixWriter(w io.Writer, prefix string) *PrefixWriter {
+	return &PrefixWriter{w: w, prefix: prefix}
+}
+
+// Write implements io.Writer
+func (p *PrefixWriter) Write(data []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.partial = append(p.partial, data...)
+	for {
+		i := bytes.IndexByte(p.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := p.partial[:i+1]
+		if _, err := io.WriteString(p.w, p.prefix+string(line)); err != nil {
+			return 0, err
+		}
+		p.partial = p.partial[i+1:]
+	}
+	// Compact so held bytes do not pin an ever-growing backing array
+	p.partial = append([]byte(nil), p.partial...)
+	return len(data), nil
+}
+
+// Flush writes any held partial line followed by a newline
+func (p *PrefixWriter) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.partial) == 0 {
+		return nil
+	}
+	_, err := io.WriteString(p.w, p.prefix+string(p.partial)+"\n")
+	p.partial = nil
+	return err
+}
@@ -0,0 +1,103 @@
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStderrBuffer(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		writes   []string
+		maxLines int
+		want     string
+	}{
+		{
+			name:     "fits",
+			size:     100,
+			writes:   []string{"starting\n", "error: missing API_KEY\n"},
+			maxLines: 10,
+			want:     "starting\nerror: missing API_KEY",
+		},
+		{
+			name:     "limits_lines",
+			size:     100,
+			writes:   []string{"one\ntwo\nthree\nfour\n"},
+			maxLines: 2,
+			want:     "three\nfour",
+		},
+		{
+			name:     "keeps_partial_last_line",
+			size:     100,
+			writes:   []string{"one\n", "tw", "o"},
+			maxLines: 10,
+			want:     "one\ntwo",
+		},
+		{
+			name:     "wraps_and_drops_cut_line",
+			size:     12,
+			writes:   []string{"aaaaaaaa\n", "bbbb\n", "cccc\n"},
+			maxLines: 10,
+			want:     "bbbb\ncccc",
+		},
+		{
+			name:     "single_write_larger_than_buffer",
+			size:     8,
+			writes:   []string{"xxxxxxxxxxxx\nlast\n"},
+			maxLines: 10,
+			want:     "last",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := NewStderrBuffer(tt.size)
+			for _, w := range tt.writes {
+				n, err := buffer.Write([]byte(w))
+				if err != nil || n != len(w) {
+					t.Fatalf("Write(%q) = %d, %v", w, n, err)
+				}
+			}
+			if got := buffer.Tail(tt.maxLines); got != tt.want {
+				t.Errorf("Tail() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStderrBuffer_Bounded(t *testing.T) {
+	buffer := NewStderrBuffer(1024)
+	for i := range 1000 {
+		_, _ = fmt.Fprintf(buffer, "line %d\n", i)
+	}
+	if len(buffer.data) > 1024 {
+		t.Errorf("Expected buffer to stay within 1024 bytes, got %d", len(buffer.data))
+	}
+	if got := buffer.Tail(1); got != "line 999" {
+		t.Errorf("Expected most recent line, got %q", got)
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	writer := NewPrefixWriter(&out, "[server] ")
+
+	for _, chunk := range []string{"listening", " on stdio\nready\n", "shutting down"} {
+		if _, err := writer.Write([]byte(chunk)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if got := out.String(); got != "[server] listening on stdio\n[server] ready\n" {
+		t.Errorf("Expected only complete lines before Flush, got %q", got)
+	}
+
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if !strings.HasSuffix(out.String(), "[server] shutting down\n") {
+		t.Errorf("Expected partial line after Flush, got %q", out.String())
+	}
+}
@@ -0,0 +1,287 @@
+package transport
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/spandigital/mcp-server-dump/internal/auth"
+)
+
+// testPKI is a throwaway CA with a server certificate for mcp.internal and a client certificate
+type testPKI struct {
+	caPool     *x509.CertPool
+	serverCert tls.Certificate
+	caFile     string
+	clientCert string
+	clientKey  string
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, caTemplate := newTestKey(t), &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("Failed to create CA: %v", err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatalf("Failed to parse CA: %v", err)
+	}
+
+	issue := func(serial int64, template *x509.Certificate) ([]byte, *ecdsa.PrivateKey) {
+		key := newTestKey(t)
+		template.SerialNumber = big.NewInt(serial)
+		template.NotBefore = time.Now().Add(-time.Hour)
+		template.NotAfter = time.Now().Add(time.Hour)
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatalf("Failed to issue certificate: %v", err)
+		}
+		return der, key
+	}
+
+	serverDER, serverKey := issue(2, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "mcp.internal"},
+		DNSNames:    []string{"mcp.internal"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	clientDER, clientKey := issue(3, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "mcp-server-dump"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	pki := &testPKI{
+		caPool:     x509.NewCertPool(),
+		serverCert: tls.Certificate{Certificate: [][]byte{serverDER}, PrivateKey: serverKey},
+		caFile:     writePEM(t, dir, "ca.pem", "CERTIFICATE", caDER),
+		clientCert: writePEM(t, dir, "client.pem", "CERTIFICATE", clientDER),
+		clientKey:  writePEM(t, dir, "client-key.pem", "EC PRIVATE KEY", marshalTestKey(t, clientKey)),
+	}
+	pki.caPool.AddCert(caCert)
+	return pki
+}
+
+func newTestKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	return key
+}
+
+func marshalTestKey(t *testing.T, key *ecdsa.PrivateKey) []byte {
+	t.Helper()
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	return der
+}
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+// newMTLSServer starts a TLS server for mcp.internal that requires a client certificate from the test CA
+func newMTLSServer(t *testing.T, pki *testPKI, handler http.Handler) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(handler)
+	server.TLS = &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{pki.serverCert},
+		ClientCAs:    pki.caPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNewHTTPTransport_MutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	server := newMTLSServer(t, pki, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{
+			name:    "system roots only",
+			config:  Config{TLSServerName: "mcp.internal"},
+			wantErr: "certificate",
+		},
+		{
+			name:    "missing client certificate",
+			config:  Config{CACert: pki.caFile, TLSServerName: "mcp.internal"},
+			wantErr: "certificate",
+		},
+		{
+			name:    "server name mismatch",
+			config:  Config{CACert: pki.caFile, ClientCert: pki.clientCert, ClientKey: pki.clientKey},
+			wantErr: "certificate",
+		},
+		{
+			name:   "ca, client certificate and server name",
+			config: Config{CACert: pki.caFile, ClientCert: pki.clientCert, ClientKey: pki.clientKey, TLSServerName: "mcp.internal"},
+		},
+		{
+			name:   "insecure skip verify",
+			config: Config{ClientCert: pki.clientCert, ClientKey: pki.clientKey, InsecureSkipVerify: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, err := NewHTTPTransport(&tt.config)
+			if err != nil {
+				t.Fatalf("NewHTTPTransport failed: %v", err)
+			}
+			client := &http.Client{Transport: base, Timeout: 5 * time.Second}
+
+			resp, err := client.Get(server.URL)
+			if tt.wantErr != "" {
+				if err == nil {
+					_ = resp.Body.Close()
+					t.Fatalf("Expected an error containing %q", tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != http.StatusNoContent {
+				t.Errorf("Expected status 204, got %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestNewHTTPTransport_InvalidOptions(t *testing.T) {
+	pki := newTestPKI(t)
+	notPEM := filepath.Join(t.TempDir(), "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{"cert without key", Config{ClientCert: pki.clientCert}, "must be provided together"},
+		{"key without cert", Config{ClientKey: pki.clientKey}, "must be provided together"},
+		{"mismatched key", Config{ClientCert: pki.clientCert, ClientKey: pki.caFile}, "failed to load client certificate"},
+		{"missing ca file", Config{CACert: filepath.Join(t.TempDir(), "missing.pem")}, "failed to read --ca-cert"},
+		{"ca file without certificates", Config{CACert: notPEM}, "no PEM certificates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewHTTPTransport(&tt.config); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCreate_StreamableOverMutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "tls-test-server", Version: "1.0.0"}, nil)
+	server := newMTLSServer(t, pki, mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return mcpServer }, nil))
+
+	mcpTransport, err := Create(&Config{
+		Transport:     "streamable",
+		Endpoint:      server.URL,
+		Timeout:       5 * time.Second,
+		CACert:        pki.caFile,
+		ClientCert:    pki.clientCert,
+		ClientKey:     pki.clientKey,
+		TLSServerName: "mcp.internal",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(context.Background(), mcpTransport, nil)
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer func() { _ = session.Close() }()
+
+	if name := session.InitializeResult().ServerInfo.Name; name != "tls-test-server" {
+		t.Errorf("Expected server name tls-test-server, got %q", name)
+	}
+}
+
+func TestNewHTTPTransport_OAuthDiscovery(t *testing.T) {
+	pki := newTestPKI(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mcp", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	var server *httptest.Server
+	mux.HandleFunc("/.well-known/oauth-authorization-server", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"issuer":"` + server.URL + `","token_endpoint":"` + server.URL + `/token"}`))
+	})
+	server = newMTLSServer(t, pki, mux)
+
+	// Without the TLS settings discovery cannot reach the server
+	if config, err := auth.DiscoverAndConfigure(context.Background(), server.URL+"/mcp"); err == nil {
+		t.Fatalf("Expected discovery without TLS settings to fail, got %+v", config)
+	}
+
+	base, err := NewHTTPTransport(&Config{CACert: pki.caFile, ClientCert: pki.clientCert, ClientKey: pki.clientKey, TLSServerName: "mcp.internal"})
+	if err != nil {
+		t.Fatalf("NewHTTPTransport failed: %v", err)
+	}
+	ctx := auth.WithHTTPTransport(context.Background(), base)
+
+	config, err := auth.DiscoverAndConfigure(ctx, server.URL+"/mcp")
+	if err != nil {
+		t.Fatalf("Discovery failed: %v", err)
+	}
+	if config == nil || config.TokenURL != server.URL+"/token" {
+		t.Errorf("Expected discovered token URL %s/token, got %+v", server.URL, config)
+	}
+}
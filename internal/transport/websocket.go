@@ -0,0 +1,417 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1" // #nosec G505 - SHA-1 is mandated by RFC 6455 for the handshake, not used for security
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// WebSocket protocol constants from RFC 6455
+const (
+	webSocketGUID        = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	webSocketSubprotocol = "mcp"
+
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+
+	closeNormal        = 1000
+	closeProtocolError = 1002
+
+	// maxControlPayload is the largest payload a control frame may carry
+	maxControlPayload = 125
+)
+
+// maxWebSocketMessageSize bounds a single (possibly fragmented) message to 32MB
+const maxWebSocketMessageSize = 32 * 1024 * 1024
+
+// WebSocketTransport connects to an MCP server that exchanges JSON-RPC messages over a
+// WebSocket, one message per text frame. The upgrade request is sent with HTTPClient, so
+// header and OAuth round trippers authenticate the handshake like any other HTTP request.
+type WebSocketTransport struct {
+	Endpoint string
+	// HTTPClient sends the upgrade request. Its Timeout must be zero, since a client
+	// timeout would also cut off the upgraded connection; use HandshakeTimeout instead.
+	HTTPClient *http.Client
+	// HandshakeTimeout bounds the upgrade request (no limit when zero)
+	HandshakeTimeout time.Duration
+}
+
+// Connect performs the WebSocket handshake and returns a connection for the MCP session
+func (t *WebSocketTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	client := t.HTTPClient
+	if client == nil {
+		client = &http.Client{Transport: NewWebSocketBaseTransport(http.DefaultTransport.(*http.Transport).Clone())}
+	}
+
+	keyBytes := make([]byte, 16)
+	if _, err := rand.Read(keyBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate WebSocket key: %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, HTTPEndpoint(t.Endpoint), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WebSocket endpoint: %w", err)
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Protocol", webSocketSubprotocol)
+
+	resp, err := doHandshake(client, req, t.HandshakeTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("WebSocket handshake failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("WebSocket handshake failed: server responded %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if err := checkHandshake(resp, key); err != nil {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("WebSocket handshake failed: %w", err)
+	}
+	rwc, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		_ = resp.Body.Close()
+		return nil, errors.New("WebSocket handshake failed: upgraded connection is not writable")
+	}
+
+	return newWebSocketConn(rwc, bufio.NewReader(rwc), true), nil
+}
+
+// doHandshake sends the upgrade request, failing if no response arrives within timeout.
+// The request context is cancelled before returning. That cannot affect an upgraded
+// connection: once net/http delivers a 101 response, the caller owns the connection and the
+// transport no longer watches the request context (it cancels the context itself).
+func doHandshake(client *http.Client, req *http.Request, timeout time.Duration) (*http.Response, error) {
+	if timeout <= 0 {
+		return client.Do(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	defer cancel()
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && req.Context().Err() == nil {
+			return nil, fmt.Errorf("timed out after %s: %w", timeout, err)
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+// NewWebSocketBaseTransport adapts base for WebSocket upgrades and returns it.
+// HTTP/2 is disabled because the upgrade mechanism only exists in HTTP/1.1.
+func NewWebSocketBaseTransport(base *http.Transport) *http.Transport {
+	base.ForceAttemptHTTP2 = false
+	base.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	return base
+}
+
+// HTTPEndpoint converts ws:// and wss:// URLs to their http:// and https:// equivalents,
+// which are used for the upgrade request and OAuth discovery. Other URLs are returned unchanged.
+func HTTPEndpoint(endpoint string) string {
+	switch {
+	case strings.HasPrefix(strings.ToLower(endpoint), "ws://"):
+		return "http://" + endpoint[len("ws://"):]
+	case strings.HasPrefix(strings.ToLower(endpoint), "wss://"):
+		return "https://" + endpoint[len("wss://"):]
+	}
+	return endpoint
+}
+
+// checkHandshake validates the upgrade response headers against the request sent with key
+func checkHandshake(resp *http.Response, key string) error {
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") || !headerHasToken(resp.Header, "Connection", "upgrade") {
+		return errors.New("server did not upgrade to websocket")
+	}
+	if protocol := resp.Header.Get("Sec-WebSocket-Protocol"); protocol != webSocketSubprotocol {
+		return fmt.Errorf("server selected subprotocol %q, want %q", protocol, webSocketSubprotocol)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != webSocketAccept(key) {
+		return errors.New("invalid Sec-WebSocket-Accept")
+	}
+	return nil
+}
+
+// headerHasToken reports whether a comma-separated header such as Connection lists token
+func headerHasToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// webSocketAccept computes the Sec-WebSocket-Accept value expected for a key
+func webSocketAccept(key string) string {
+	hash := sha1.Sum([]byte(key + webSocketGUID)) // #nosec G401 - required by RFC 6455
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// webSocketMessage is a complete message, or the error that ended the read loop
+type webSocketMessage struct {
+	data []byte
+	err  error
+}
+
+// webSocketConn implements mcp.Connection over an established WebSocket. Frames sent by the
+// client are masked as RFC 6455 requires; the server role is only used in tests.
+type webSocketConn struct {
+	rwc    io.ReadWriteCloser
+	br     *bufio.Reader
+	client bool
+
+	writeMu   sync.Mutex
+	incoming  chan webSocketMessage
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// newWebSocketConn wraps an upgraded connection and starts reading messages from it
+func newWebSocketConn(rwc io.ReadWriteCloser, br *bufio.Reader, client bool) *webSocketConn {
+	c := &webSocketConn{
+		rwc:      rwc,
+		br:       br,
+		client:   client,
+		incoming: make(chan webSocketMessage),
+		closed:   make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+// readLoop reads messages in the background so Read can honour its context and Close
+func (c *webSocketConn) readLoop() {
+	for {
+		data, err := c.readMessage()
+		select {
+		case c.incoming <- webSocketMessage{data: data, err: err}:
+		case <-c.closed:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// readMessage reads frames until a complete data message arrives, answering pings and
+// treating a close frame as the end of the stream. Frames that break the fragmentation rules
+// of RFC 6455 fail the connection.
+func (c *webSocketConn) readMessage() ([]byte, error) {
+	var message []byte
+	inMessage := false // a fragmented message has started and not yet finished
+	for {
+		fin, opcode, payload, err := readFrame(c.br)
+		if err != nil {
+			return nil, err
+		}
+
+		// Control frames may arrive between fragments but cannot be fragmented themselves
+		if opcode&0x8 != 0 && (!fin || len(payload) > maxControlPayload) {
+			return nil, c.fail(fmt.Errorf("invalid WebSocket control frame %#x: fragmented or longer than %d bytes", opcode, maxControlPayload))
+		}
+
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			// Echo the close frame to complete the closing handshake
+			_ = c.writeFrame(opClose, payload)
+			return nil, io.EOF
+		case opText, opBinary:
+			if inMessage {
+				return nil, c.fail(errors.New("WebSocket data frame received before the previous fragmented message ended"))
+			}
+		case opContinuation:
+			if !inMessage {
+				return nil, c.fail(errors.New("WebSocket continuation frame received outside a fragmented message"))
+			}
+		default:
+			return nil, c.fail(fmt.Errorf("unsupported WebSocket opcode %#x", opcode))
+		}
+		inMessage = !fin
+
+		if len(message)+len(payload) > maxWebSocketMessageSize {
+			return nil, fmt.Errorf("WebSocket message exceeds maximum size of %d bytes", maxWebSocketMessageSize)
+		}
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+// fail sends a protocol error close frame and returns err, failing the connection
+func (c *webSocketConn) fail(err error) error {
+	_ = c.writeFrame(opClose, binary.BigEndian.AppendUint16(nil, closeProtocolError))
+	return err
+}
+
+// Read returns the next JSON-RPC message from the server
+func (c *webSocketConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.closed:
+		return nil, io.EOF
+	case msg := <-c.incoming:
+		if msg.err != nil {
+			return nil, msg.err
+		}
+		return jsonrpc.DecodeMessage(msg.data)
+	}
+}
+
+// Write sends a JSON-RPC message as a single text frame
+func (c *webSocketConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data, err := jsonrpc.EncodeMessage(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	return c.writeFrame(opText, data)
+}
+
+// Close sends a normal closure frame and closes the underlying connection
+func (c *webSocketConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		payload := binary.BigEndian.AppendUint16(nil, closeNormal)
+		_ = c.writeFrame(opClose, payload)
+		err = c.rwc.Close()
+	})
+	return err
+}
+
+// SessionID implements mcp.Connection; WebSocket sessions have no transport-level ID
+func (c *webSocketConn) SessionID() string { return "" }
+
+// writeFrame writes a single unfragmented frame, serialising concurrent writers
+func (c *webSocketConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return writeFrame(c.rwc, opcode, payload, c.client)
+}
+
+// writeFrame encodes a final frame, masking the payload when sent by a client
+func writeFrame(w io.Writer, opcode byte, payload []byte, mask bool) error {
+	header := []byte{0x80 | opcode}
+
+	maskBit := byte(0)
+	if mask {
+		maskBit = 0x80
+	}
+	switch length := len(payload); {
+	case length <= 125:
+		header = append(header, maskBit|byte(length))
+	case length <= 0xFFFF:
+		header = append(header, maskBit|126)
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header = append(header, maskBit|127)
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	if mask {
+		key := make([]byte, 4)
+		if _, err := rand.Read(key); err != nil {
+			return fmt.Errorf("failed to generate WebSocket mask: %w", err)
+		}
+		header = append(header, key...)
+		masked := make([]byte, len(payload))
+		for i, b := range payload {
+			masked[i] = b ^ key[i%4]
+		}
+		payload = masked
+	}
+
+	_, err := w.Write(append(header, payload...))
+	return err
+}
+
+// readFrame decodes a single frame, unmasking the payload if needed
+func readFrame(r *bufio.Reader) (fin bool, opcode byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err = io.ReadFull(r, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	if head[0]&0x70 != 0 {
+		return false, 0, nil, errors.New("WebSocket frame uses reserved bits")
+	}
+	fin = head[0]&0x80 != 0
+	opcode = head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxWebSocketMessageSize {
+		return false, 0, nil, fmt.Errorf("WebSocket frame exceeds maximum size of %d bytes", maxWebSocketMessageSize)
+	}
+
+	var key [4]byte
+	if masked {
+		if _, err = io.ReadFull(r, key[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= key[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
@@ -0,0 +1,344 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// connTransport hands an existing connection to an MCP server
+type connTransport struct {
+	conn mcp.Connection
+}
+
+func (t *connTransport) Connect(context.Context) (mcp.Connection, error) { return t.conn, nil }
+
+// newWebSocketMCPServer starts an httptest server that upgrades requests to WebSocket and
+// serves an MCP server with a single echo tool. Upgrade requests are passed to check first.
+func newWebSocketMCPServer(t *testing.T, check func(r *http.Request) int) *httptest.Server {
+	t.Helper()
+
+	mcpServer := mcp.NewServer(&mcp.Implementation{Name: "ws-test-server", Version: "1.0.0"}, nil)
+	type echoArgs struct {
+		Text string `json:"text"`
+	}
+	mcp.AddTool(mcpServer, &mcp.Tool{Name: "echo", Description: "Echoes text"}, func(_ context.Context, _ *mcp.CallToolRequest, args echoArgs) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: args.Text}}}, nil, nil
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status := check(r); status != http.StatusSwitchingProtocols {
+			http.Error(w, "rejected", status)
+			return
+		}
+		if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || r.Header.Get("Sec-WebSocket-Version") != "13" {
+			http.Error(w, "not a websocket upgrade", http.StatusBadRequest)
+			return
+		}
+
+		netConn, brw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			t.Errorf("Hijack failed: %v", err)
+			return
+		}
+		fmt.Fprintf(brw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\nSec-WebSocket-Protocol: mcp\r\n\r\n",
+			webSocketAccept(r.Header.Get("Sec-WebSocket-Key")))
+		if err := brw.Flush(); err != nil {
+			t.Errorf("Failed to write handshake: %v", err)
+			return
+		}
+
+		conn := newWebSocketConn(netConn, brw.Reader, false)
+		session, err := mcpServer.Connect(context.Background(), &connTransport{conn: conn}, nil)
+		if err != nil {
+			t.Errorf("Server connect failed: %v", err)
+			return
+		}
+		_ = session.Wait()
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWebSocketTransport_MCPSession(t *testing.T) {
+	var gotAuth, gotProtocol string
+	server := newWebSocketMCPServer(t, func(r *http.Request) int {
+		gotAuth = r.Header.Get("Authorization")
+		gotProtocol = r.Header.Get("Sec-WebSocket-Protocol")
+		return http.StatusSwitchingProtocols
+	})
+
+	transport, err := Create(&Config{
+		Transport: "websocket",
+		Endpoint:  "ws" + strings.TrimPrefix(server.URL, "http"),
+		Headers:   []string{"Authorization:Bearer test-token"},
+		Timeout:   50 * time.Millisecond,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	ctx := context.Background()
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, transport, nil)
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer func() { _ = session.Close() }()
+
+	if gotAuth != "Bearer test-token" {
+		t.Errorf("Expected Authorization header on the upgrade request, got %q", gotAuth)
+	}
+	if gotProtocol != "mcp" {
+		t.Errorf("Expected mcp subprotocol, got %q", gotProtocol)
+	}
+	if name := session.InitializeResult().ServerInfo.Name; name != "ws-test-server" {
+		t.Errorf("Expected server name ws-test-server, got %q", name)
+	}
+
+	// The handshake timeout must not cut off the established connection
+	time.Sleep(100 * time.Millisecond)
+
+	tools, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	if len(tools.Tools) != 1 || tools.Tools[0].Name != "echo" {
+		t.Fatalf("Unexpected tools: %+v", tools.Tools)
+	}
+
+	// Large enough to need a 64-bit length frame in one direction and a 16-bit one in the other
+	text := strings.Repeat("x", 70000)
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "echo", Arguments: map[string]any{"text": text}})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if got := result.Content[0].(*mcp.TextContent).Text; got != text {
+		t.Errorf("Echoed text mismatch: got %d bytes", len(got))
+	}
+}
+
+func TestWebSocketTransport_HandshakeRejected(t *testing.T) {
+	server := newWebSocketMCPServer(t, func(*http.Request) int { return http.StatusUnauthorized })
+
+	transport := &WebSocketTransport{Endpoint: server.URL}
+	_, err := transport.Connect(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("Expected handshake to fail with 401, got %v", err)
+	}
+}
+
+func TestWebSocketTransport_HandshakeTimeout(t *testing.T) {
+	// The server accepts the connection but never answers the upgrade request
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+	go func() {
+		for {
+			conn, acceptErr := listener.Accept()
+			if acceptErr != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+		}
+	}()
+
+	transport := &WebSocketTransport{Endpoint: "ws://" + listener.Addr().String(), HandshakeTimeout: 50 * time.Millisecond}
+	_, err = transport.Connect(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "timed out after 50ms") {
+		t.Fatalf("Expected the handshake to time out, got %v", err)
+	}
+}
+
+func TestWebSocketTransport_RequiresEndpoint(t *testing.T) {
+	if _, err := Create(&Config{Transport: "websocket"}, nil); err == nil || !strings.Contains(err.Error(), "requires --endpoint") {
+		t.Errorf("Expected missing endpoint error, got %v", err)
+	}
+}
+
+func TestWebSocketConn_ControlAndFragmentedFrames(t *testing.T) {
+	clientSide, serverSide := net.Pipe()
+	conn := newWebSocketConn(clientSide, bufio.NewReader(clientSide), true)
+	// net.Pipe is unbuffered, so close the server side first or the close frame blocks
+	defer func() { _ = conn.Close() }()
+	defer func() { _ = serverSide.Close() }()
+	serverReader := bufio.NewReader(serverSide)
+
+	// A ping is answered with a pong carrying the same payload
+	go func() { _ = writeFrame(serverSide, opPing, []byte("hello"), false) }()
+	fin, opcode, payload, err := readFrame(serverReader)
+	if err != nil {
+		t.Fatalf("Failed to read pong: %v", err)
+	}
+	if !fin || opcode != opPong || string(payload) != "hello" {
+		t.Errorf("Expected pong with payload hello, got fin=%v opcode=%#x payload=%q", fin, opcode, payload)
+	}
+
+	// A message split across a text frame and a continuation frame is reassembled
+	message := `{"jsonrpc":"2.0","method":"notifications/initialized"}`
+	go func() {
+		_, _ = serverSide.Write(append([]byte{opText, byte(20)}, message[:20]...))
+		_ = writeFrame(serverSide, opContinuation, []byte(message[20:]), false)
+	}()
+
+	msg, err := conn.Read(context.Background())
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if req, ok := msg.(*jsonrpc.Request); !ok || req.Method != "notifications/initialized" {
+		t.Errorf("Unexpected message: %#v", msg)
+	}
+
+	// Frames written by the client are masked
+	go func() {
+		_ = conn.Write(context.Background(), &jsonrpc.Request{Method: "ping"})
+	}()
+	var head [2]byte
+	if _, err := serverReader.Read(head[:]); err != nil {
+		t.Fatalf("Failed to read client frame: %v", err)
+	}
+	if head[1]&0x80 == 0 {
+		t.Error("Expected client frame to be masked")
+	}
+}
+
+func TestWebSocketConn_ProtocolViolations(t *testing.T) {
+	// rawFrame builds an unmasked server frame with the given FIN bit and opcode
+	rawFrame := func(fin bool, opcode byte, payload []byte) []byte {
+		head := opcode
+		if fin {
+			head |= 0x80
+		}
+		frame := []byte{head}
+		if len(payload) <= 125 {
+			frame = append(frame, byte(len(payload)))
+		} else {
+			frame = append(frame, 126, byte(len(payload)>>8), byte(len(payload)))
+		}
+		return append(frame, payload...)
+	}
+
+	tests := []struct {
+		name    string
+		frames  [][]byte
+		wantErr string
+	}{
+		{
+			name:    "continuation_without_message",
+			frames:  [][]byte{rawFrame(true, opContinuation, []byte(`{}`))},
+			wantErr: "continuation frame received outside a fragmented message",
+		},
+		{
+			name: "data_frame_inside_fragmented_message",
+			frames: [][]byte{
+				rawFrame(false, opText, []byte(`{"jsonrpc":`)),
+				rawFrame(true, opText, []byte(`{"jsonrpc":"2.0","method":"ping"}`)),
+			},
+			wantErr: "before the previous fragmented message ended",
+		},
+		{
+			name:    "fragmented_control_frame",
+			frames:  [][]byte{rawFrame(false, opPing, []byte("hello"))},
+			wantErr: "invalid WebSocket control frame",
+		},
+		{
+			name:    "oversized_control_frame",
+			frames:  [][]byte{rawFrame(true, opPing, []byte(strings.Repeat("x", 126)))},
+			wantErr: "invalid WebSocket control frame",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientSide, serverSide := net.Pipe()
+			conn := newWebSocketConn(clientSide, bufio.NewReader(clientSide), true)
+			defer func() { _ = conn.Close() }()
+			defer func() { _ = serverSide.Close() }()
+
+			go func() {
+				for _, frame := range tt.frames {
+					if _, err := serverSide.Write(frame); err != nil {
+						return
+					}
+				}
+			}()
+
+			// The connection is failed with a protocol error close frame
+			fin, opcode, payload, err := readFrame(bufio.NewReader(serverSide))
+			if err != nil {
+				t.Fatalf("Failed to read close frame: %v", err)
+			}
+			if !fin || opcode != opClose || len(payload) < 2 || int(payload[0])<<8|int(payload[1]) != closeProtocolError {
+				t.Errorf("Expected a protocol error close frame, got fin=%v opcode=%#x payload=%v", fin, opcode, payload)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if _, err := conn.Read(ctx); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected read error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWebSocketTransport_HandshakeValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers string
+		wantErr string
+	}{
+		{"missing_connection_upgrade", "Upgrade: websocket\r\nSec-WebSocket-Protocol: mcp\r\n", "did not upgrade"},
+		{"wrong_subprotocol", "Upgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Protocol: chat\r\n", `subprotocol "chat"`},
+		{"missing_subprotocol", "Upgrade: websocket\r\nConnection: keep-alive, Upgrade\r\n", `subprotocol ""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				netConn, brw, err := http.NewResponseController(w).Hijack()
+				if err != nil {
+					t.Errorf("Hijack failed: %v", err)
+					return
+				}
+				defer func() { _ = netConn.Close() }()
+				fmt.Fprintf(brw, "HTTP/1.1 101 Switching Protocols\r\n%sSec-WebSocket-Accept: %s\r\n\r\n",
+					tt.headers, webSocketAccept(r.Header.Get("Sec-WebSocket-Key")))
+				_ = brw.Flush()
+				_, _ = brw.ReadByte() // Hold the connection until the client gives up on it
+			}))
+			defer server.Close()
+
+			transport := &WebSocketTransport{Endpoint: server.URL}
+			_, err := transport.Connect(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected handshake error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestHTTPEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"ws://localhost:8080/mcp":  "http://localhost:8080/mcp",
+		"wss://example.com/mcp":    "https://example.com/mcp",
+		"WSS://example.com/mcp":    "https://example.com/mcp",
+		"https://example.com/mcp":  "https://example.com/mcp",
+		"http://localhost:3001/ws": "http://localhost:3001/ws",
+	}
+	for input, want := range tests {
+		if got := HTTPEndpoint(input); got != want {
+			t.Errorf("HTTPEndpoint(%q) = %q, want %q", input, got, want)
+		}
+	}
+}